@@ -0,0 +1,297 @@
+package scorer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	// DefaultAnthropicBaseURL is the public Anthropic API endpoint
+	DefaultAnthropicBaseURL = "https://api.anthropic.com/v1"
+
+	// DefaultAnthropicVersion is the Messages API version sent in the anthropic-version header
+	DefaultAnthropicVersion = "2023-06-01"
+
+	// DefaultAnthropicModel is used when an Anthropic-backed scorer has no model configured
+	DefaultAnthropicModel = "claude-3-5-haiku-latest"
+
+	// defaultAnthropicMaxTokens is used when the request does not set an output limit,
+	// since the Messages API requires max_tokens on every call
+	defaultAnthropicMaxTokens = 4096
+)
+
+// AnthropicConfig holds the settings for talking to the Anthropic Messages API
+type AnthropicConfig struct {
+	APIKey     string       // Anthropic API key (required)
+	BaseURL    string       // API base URL (default: DefaultAnthropicBaseURL)
+	Version    string       // anthropic-version header (default: DefaultAnthropicVersion)
+	MaxTokens  int          // Output token limit when the request sets none (default: 4096)
+	HTTPClient *http.Client // Custom HTTP client (default: &http.Client{})
+}
+
+// AnthropicClient adapts the Anthropic Messages API to the OpenAIClient interface.
+//
+// Chat completion requests are translated into Messages API calls. When the
+// request asks for JSON schema output, the schema is sent as a single tool and
+// the model is forced to call it, so the tool input comes back as the message
+// content exactly as OpenAI's structured output would. This lets the scorer's
+// batching, retry, circuit breaker and metrics layers run unchanged.
+type AnthropicClient struct {
+	config     AnthropicConfig
+	httpClient *http.Client
+}
+
+// NewAnthropicClient creates a new Anthropic Messages API client
+func NewAnthropicClient(config AnthropicConfig) *AnthropicClient {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultAnthropicBaseURL
+	}
+	if config.Version == "" {
+		config.Version = DefaultAnthropicVersion
+	}
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = defaultAnthropicMaxTokens
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	return &AnthropicClient{
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// Anthropic Messages API wire types
+type anthropicRequest struct {
+	Model       string               `json:"model"`
+	System      string               `json:"system,omitempty"`
+	Messages    []anthropicMessage   `json:"messages"`
+	MaxTokens   int                  `json:"max_tokens"`
+	Temperature *float32             `json:"temperature,omitempty"`
+	Tools       []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type anthropicResponse struct {
+	ID         string                  `json:"id"`
+	Model      string                  `json:"model"`
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+type anthropicErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CreateChatCompletion sends the request to the Messages API and converts the
+// reply back into an OpenAI chat completion response
+func (c *AnthropicClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	body, toolName, err := c.buildRequest(req)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("failed to encode Anthropic request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(c.config.BaseURL, "/")+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("failed to create Anthropic request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.config.APIKey)
+	httpReq.Header.Set("anthropic-version", c.config.Version)
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("failed to read Anthropic response: %w", err)
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return openai.ChatCompletionResponse{}, newAnthropicAPIError(httpResp, respBody)
+	}
+
+	var resp anthropicResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("failed to decode Anthropic response: %w", err)
+	}
+
+	return convertAnthropicResponse(resp, toolName), nil
+}
+
+// buildRequest translates an OpenAI chat completion request into a Messages API
+// request. It returns the name of the forced tool when structured output was requested.
+func (c *AnthropicClient) buildRequest(req openai.ChatCompletionRequest) (anthropicRequest, string, error) {
+	body := anthropicRequest{
+		Model:     req.Model,
+		MaxTokens: c.config.MaxTokens,
+	}
+
+	if req.MaxCompletionTokens > 0 {
+		body.MaxTokens = req.MaxCompletionTokens
+	} else if req.MaxTokens > 0 {
+		body.MaxTokens = req.MaxTokens
+	}
+
+	// A set temperature is non-zero in the request, with an explicit zero
+	// carried as zeroTemperature
+	if req.Temperature != 0 {
+		temperature := req.Temperature
+		if temperature == zeroTemperature {
+			temperature = 0
+		}
+		body.Temperature = &temperature
+	}
+
+	var system []string
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case openai.ChatMessageRoleSystem:
+			system = append(system, msg.Content)
+		case openai.ChatMessageRoleUser, openai.ChatMessageRoleAssistant:
+			body.Messages = append(body.Messages, anthropicMessage{
+				Role:    msg.Role,
+				Content: msg.Content,
+			})
+		default:
+			return anthropicRequest{}, "", fmt.Errorf("unsupported message role for Anthropic: %s", msg.Role)
+		}
+	}
+	body.System = strings.Join(system, "\n\n")
+
+	var toolName string
+	if rf := req.ResponseFormat; rf != nil && rf.Type == openai.ChatCompletionResponseFormatTypeJSONSchema && rf.JSONSchema != nil {
+		toolName = rf.JSONSchema.Name
+		body.Tools = []anthropicTool{{
+			Name:        toolName,
+			Description: "Record the structured response. Always call this tool with the complete result.",
+			InputSchema: rf.JSONSchema.Schema,
+		}}
+		body.ToolChoice = &anthropicToolChoice{Type: "tool", Name: toolName}
+	}
+
+	return body, toolName, nil
+}
+
+// convertAnthropicResponse maps a Messages API response onto the OpenAI response
+// shape. The forced tool's input becomes the message content so callers can parse
+// it the same way as OpenAI structured output.
+func convertAnthropicResponse(resp anthropicResponse, toolName string) openai.ChatCompletionResponse {
+	var text strings.Builder
+	var toolInput string
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			if toolName != "" && block.Name == toolName {
+				toolInput = string(block.Input)
+			}
+		}
+	}
+
+	content := text.String()
+	if toolInput != "" {
+		content = toolInput
+	}
+
+	return openai.ChatCompletionResponse{
+		ID:     resp.ID,
+		Object: "chat.completion",
+		Model:  resp.Model,
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Message: openai.ChatCompletionMessage{
+					Role:    openai.ChatMessageRoleAssistant,
+					Content: content,
+				},
+				FinishReason: anthropicFinishReason(resp.StopReason),
+			},
+		},
+		Usage: openai.Usage{
+			PromptTokens:     resp.Usage.InputTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
+		},
+	}
+}
+
+// anthropicFinishReason maps Anthropic stop reasons onto OpenAI finish reasons
+func anthropicFinishReason(stopReason string) openai.FinishReason {
+	switch stopReason {
+	case "max_tokens":
+		return openai.FinishReasonLength
+	case "refusal":
+		return openai.FinishReasonContentFilter
+	default:
+		return openai.FinishReasonStop
+	}
+}
+
+// newAnthropicAPIError converts an Anthropic error response into an *openai.APIError
+// so retry and circuit breaker classification treat both providers alike
+func newAnthropicAPIError(resp *http.Response, body []byte) error {
+	apiErr := &openai.APIError{
+		HTTPStatus:     resp.Status,
+		HTTPStatusCode: resp.StatusCode,
+		Message:        strings.TrimSpace(string(body)),
+	}
+
+	var errResp anthropicErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+		apiErr.Type = errResp.Error.Type
+		apiErr.Code = errResp.Error.Type
+		apiErr.Message = errResp.Error.Message
+	}
+
+	return apiErr
+}
@@ -0,0 +1,226 @@
+// Package scorer_test validates the Anthropic Messages API backend against a
+// local httptest stand-in, covering request translation, forced tool use,
+// response conversion and error mapping.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+)
+
+var _ = Describe("AnthropicClient", func() {
+	var (
+		ctx      context.Context
+		server   *httptest.Server
+		mu       sync.Mutex
+		received map[string]interface{}
+		headers  http.Header
+		status   int
+		reply    string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		status = http.StatusOK
+		received = nil
+		reply = `{
+			"id": "msg_1",
+			"model": "claude-3-5-haiku-latest",
+			"stop_reason": "tool_use",
+			"content": [
+				{"type": "text", "text": "Scoring now."},
+				{"type": "tool_use", "name": "score_response",
+				 "input": {"version":"1.0","scores":[{"item_id":"1","score":91,"reason":"names a venue"}]}}
+			],
+			"usage": {"input_tokens": 120, "output_tokens": 30}
+		}`
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			headers = r.Header.Clone()
+			received = nil
+			_ = json.Unmarshal(body, &received)
+			mu.Unlock()
+
+			Expect(r.URL.Path).To(Equal("/v1/messages"))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, reply)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("scoring through NewScorer", func() {
+		It("should force the schema tool and map the tool input back to scores", func() {
+			cfg := scorer.NewAnthropicConfig("anthropic-key").WithBaseURL(server.URL + "/v1")
+			s, err := scorer.NewScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, []scorer.TextItem{
+				{ID: "1", Content: "Open mic night at The Railway, Friday 8pm"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Score).To(Equal(91))
+			Expect(results[0].Reason).To(Equal("names a venue"))
+
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(headers.Get("x-api-key")).To(Equal("anthropic-key"))
+			Expect(headers.Get("anthropic-version")).To(Equal(scorer.DefaultAnthropicVersion))
+			Expect(received["model"]).To(Equal(scorer.DefaultAnthropicModel))
+			Expect(received["system"]).ToNot(BeEmpty())
+			Expect(received["max_tokens"]).To(BeNumerically(">", 0))
+			Expect(received["tool_choice"]).To(Equal(map[string]interface{}{
+				"type": "tool",
+				"name": "score_response",
+			}))
+
+			tools := received["tools"].([]interface{})
+			Expect(tools).To(HaveLen(1))
+			tool := tools[0].(map[string]interface{})
+			Expect(tool["name"]).To(Equal("score_response"))
+			Expect(tool["input_schema"]).To(HaveKey("properties"))
+
+			messages := received["messages"].([]interface{})
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].(map[string]interface{})["role"]).To(Equal("user"))
+		})
+	})
+
+	DescribeTable("should send the configured temperature",
+		func(cfg scorer.Config, opts []scorer.ScoringOption, expected interface{}) {
+			s, err := scorer.NewScorer(cfg.WithBaseURL(server.URL + "/v1"))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s.ScoreTexts(ctx, []scorer.TextItem{{ID: "1", Content: "Open mic"}}, opts...)
+			Expect(err).ToNot(HaveOccurred())
+
+			mu.Lock()
+			defer mu.Unlock()
+			if expected == nil {
+				Expect(received).ToNot(HaveKey("temperature"))
+			} else {
+				Expect(received["temperature"]).To(BeNumerically("~", expected, 1e-6))
+			}
+		},
+		Entry("unset", scorer.NewAnthropicConfig("anthropic-key"), nil, nil),
+		Entry("zero on the config", scorer.NewAnthropicConfig("anthropic-key").WithTemperature(0), nil, 0.0),
+		Entry("zero per request", scorer.NewAnthropicConfig("anthropic-key").WithTemperature(0.5),
+			[]scorer.ScoringOption{scorer.WithTemperature(0)}, 0.0),
+		Entry("non-zero", scorer.NewAnthropicConfig("anthropic-key").WithTemperature(0.5), nil, 0.5),
+	)
+
+	Describe("CreateChatCompletion", func() {
+		var client *scorer.AnthropicClient
+
+		BeforeEach(func() {
+			client = scorer.NewAnthropicClient(scorer.AnthropicConfig{
+				APIKey:  "anthropic-key",
+				BaseURL: server.URL + "/v1",
+			})
+		})
+
+		It("should return text content and usage when no schema is requested", func() {
+			reply = `{"id":"msg_2","model":"claude","stop_reason":"max_tokens",
+				"content":[{"type":"text","text":"partial"}],
+				"usage":{"input_tokens":10,"output_tokens":5}}`
+
+			resp, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+				Model:     "claude",
+				MaxTokens: 64,
+				Messages: []openai.ChatCompletionMessage{
+					{Role: openai.ChatMessageRoleUser, Content: "hello"},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.Choices).To(HaveLen(1))
+			Expect(resp.Choices[0].Message.Content).To(Equal("partial"))
+			Expect(resp.Choices[0].FinishReason).To(Equal(openai.FinishReasonLength))
+			Expect(resp.Usage.TotalTokens).To(Equal(15))
+
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(received).ToNot(HaveKey("tools"))
+			Expect(received["max_tokens"]).To(BeNumerically("==", 64))
+		})
+
+		It("should convert error responses into retryable API errors", func() {
+			status = 529
+			reply = `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`
+
+			_, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+				Model: "claude",
+				Messages: []openai.ChatCompletionMessage{
+					{Role: openai.ChatMessageRoleUser, Content: "hello"},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+
+			var apiErr *openai.APIError
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.HTTPStatusCode).To(Equal(529))
+			Expect(apiErr.Type).To(Equal("overloaded_error"))
+			Expect(apiErr.Message).To(Equal("Overloaded"))
+			Expect(scorer.IsRetryableError(err)).To(BeTrue())
+		})
+
+		It("should treat authentication failures as non-retryable", func() {
+			status = http.StatusUnauthorized
+			reply = `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`
+
+			_, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+				Model: "claude",
+				Messages: []openai.ChatCompletionMessage{
+					{Role: openai.ChatMessageRoleUser, Content: "hello"},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(scorer.IsRetryableError(err)).To(BeFalse())
+			Expect(scorer.ShouldTripCircuit(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Config", func() {
+		It("should accept non-OpenAI model names for the Anthropic provider", func() {
+			cfg := scorer.NewAnthropicConfig("anthropic-key").WithModel("claude-sonnet-4-0")
+			Expect(cfg.Validate()).To(Succeed())
+		})
+
+		It("should reject temperatures above 1", func() {
+			cfg := scorer.NewAnthropicConfig("anthropic-key").WithTemperature(1.5)
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("temperature must be between 0 and 1")))
+			_, err := scorer.NewScorer(cfg)
+			Expect(err).To(MatchError(ContainSubstring("temperature must be between 0 and 1")))
+
+			s, err := scorer.NewScorer(scorer.NewAnthropicConfig("anthropic-key").WithBaseURL(server.URL + "/v1"))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = s.ScoreTexts(ctx, []scorer.TextItem{{ID: "1", Content: "Open mic"}}, scorer.WithTemperature(1.5))
+			Expect(err).To(MatchError(ContainSubstring("temperature must be between 0 and 1")))
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(received).To(BeNil())
+		})
+
+		It("should reject unknown providers", func() {
+			cfg := scorer.NewDefaultConfig("key")
+			cfg.Provider = "mystery"
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("unsupported provider")))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package scorer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultAzureAPIVersion is the Azure OpenAI API version used when none is configured.
+// It is the first GA-track version that accepts json_schema response formats.
+const DefaultAzureAPIVersion = "2024-08-01-preview"
+
+// azureDeploymentNameChars matches characters Azure does not allow in deployment names
+var azureDeploymentNameChars = regexp.MustCompile(`[.:]`)
+
+// AzureConfig holds Azure OpenAI resource settings
+type AzureConfig struct {
+	Endpoint    string            // Resource endpoint, e.g. https://my-resource.openai.azure.com
+	APIVersion  string            // API version query parameter (default: DefaultAzureAPIVersion)
+	Deployments map[string]string // Model name to deployment name mapping
+}
+
+// NewAzureConfig creates a config for an Azure OpenAI resource. Deployments maps
+// the model names used in Config.Model and WithModel to the resource's deployment names.
+func NewAzureConfig(apiKey, endpoint string, deployments map[string]string) Config {
+	if apiKey == "" {
+		panic("API key is required")
+	}
+
+	return Config{
+		Provider:      ProviderAzure,
+		APIKey:        apiKey,
+		Model:         openai.GPT4oMini,
+		MaxConcurrent: 1,
+		Timeout:       30 * time.Second,
+		AzureConfig: &AzureConfig{
+			Endpoint:    endpoint,
+			APIVersion:  DefaultAzureAPIVersion,
+			Deployments: deployments,
+		},
+	}
+}
+
+// WithAzureDeployment maps a model name to an Azure deployment name
+func (c Config) WithAzureDeployment(model, deployment string) Config {
+	azure := AzureConfig{}
+	if c.AzureConfig != nil {
+		azure = *c.AzureConfig
+	}
+
+	// Copy the map so configs derived from the same base stay independent
+	deployments := make(map[string]string, len(azure.Deployments)+1)
+	for k, v := range azure.Deployments {
+		deployments[k] = v
+	}
+	deployments[model] = deployment
+	azure.Deployments = deployments
+
+	c.AzureConfig = &azure
+	return c
+}
+
+// DeploymentFor returns the deployment name for a model. Unmapped models fall
+// back to the model name with the characters Azure disallows removed, matching
+// Azure's own naming convention (gpt-3.5-turbo becomes gpt-35-turbo).
+func (a *AzureConfig) DeploymentFor(model string) string {
+	if a != nil {
+		if deployment, ok := a.Deployments[model]; ok {
+			return deployment
+		}
+	}
+	return azureDeploymentNameChars.ReplaceAllString(model, "")
+}
+
+// validate checks the Azure settings for an Azure-backed config
+func (a *AzureConfig) validate(model string) error {
+	if a == nil {
+		return errors.New("azure provider requires AzureConfig")
+	}
+
+	if a.Endpoint == "" {
+		return errors.New("azure endpoint is required")
+	}
+	u, err := url.Parse(a.Endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid azure endpoint: %s", a.Endpoint)
+	}
+
+	if len(a.Deployments) > 0 && model != "" {
+		if _, ok := a.Deployments[model]; !ok {
+			return fmt.Errorf("no azure deployment configured for model: %s", model)
+		}
+	}
+
+	return nil
+}
+
+// newAzureClient builds a go-openai client that authenticates with the api-key
+// header and routes each model to its deployment
+func newAzureClient(cfg Config) OpenAIClient {
+	azure := cfg.AzureConfig
+	if azure == nil {
+		azure = &AzureConfig{}
+	}
+
+	endpoint := azure.Endpoint
+	if cfg.BaseURL != "" {
+		endpoint = cfg.BaseURL
+	}
+
+	clientConfig := openai.DefaultAzureConfig(cfg.APIKey, endpoint)
+	clientConfig.APIVersion = azure.APIVersion
+	if clientConfig.APIVersion == "" {
+		clientConfig.APIVersion = DefaultAzureAPIVersion
+	}
+	clientConfig.AzureModelMapperFunc = azure.DeploymentFor
+	clientConfig.HTTPClient = newHTTPDoer(cfg.HTTPClient, cfg.ProjectID)
+
+	return openai.NewClientWithConfig(clientConfig)
+}
@@ -0,0 +1,146 @@
+// Package scorer_test verifies Azure OpenAI support: deployment routing for
+// Config.Model and WithModel, api-key authentication, API version handling and
+// configuration validation, all against a local httptest server.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+)
+
+var _ = Describe("Azure OpenAI", func() {
+	var (
+		ctx      context.Context
+		server   *httptest.Server
+		mu       sync.Mutex
+		paths    []string
+		queries  []string
+		apiKeys  []string
+		bearers  []string
+		items    []scorer.TextItem
+		deployed map[string]string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		paths, queries, apiKeys, bearers = nil, nil, nil, nil
+		items = []scorer.TextItem{{ID: "1", Content: "Comedy night at the Komedia"}}
+		deployed = map[string]string{
+			openai.GPT4oMini: "scoring-mini",
+			openai.GPT4o:     "scoring-large",
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			paths = append(paths, r.URL.Path)
+			queries = append(queries, r.URL.Query().Get("api-version"))
+			apiKeys = append(apiKeys, r.Header.Get("api-key"))
+			bearers = append(bearers, r.Header.Get("Authorization"))
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+				Choices: []openai.ChatCompletionChoice{
+					{Message: openai.ChatCompletionMessage{
+						Role:    openai.ChatMessageRoleAssistant,
+						Content: `{"version":"1.0","scores":[{"item_id":"1","score":80,"reason":"event"}]}`,
+					}},
+				},
+			})
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should route Config.Model to its deployment with api-key auth", func() {
+		cfg := scorer.NewAzureConfig("azure-key", server.URL, deployed)
+		Expect(cfg.Validate()).To(Succeed())
+
+		s, err := scorer.NewScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(80))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(paths).To(Equal([]string{"/openai/deployments/scoring-mini/chat/completions"}))
+		Expect(queries).To(Equal([]string{scorer.DefaultAzureAPIVersion}))
+		Expect(apiKeys).To(Equal([]string{"azure-key"}))
+		Expect(bearers).To(Equal([]string{""}))
+	})
+
+	It("should route per-request WithModel overrides to their deployment", func() {
+		s, err := scorer.NewScorer(scorer.NewAzureConfig("azure-key", server.URL, deployed))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items, scorer.WithModel(openai.GPT4o))
+		Expect(err).ToNot(HaveOccurred())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(paths).To(Equal([]string{"/openai/deployments/scoring-large/chat/completions"}))
+	})
+
+	It("should honour a custom API version", func() {
+		cfg := scorer.NewAzureConfig("azure-key", server.URL, deployed)
+		cfg.AzureConfig.APIVersion = "2024-10-21"
+
+		s, err := scorer.NewScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(queries).To(Equal([]string{"2024-10-21"}))
+	})
+
+	Describe("deployment mapping", func() {
+		It("should add deployments without mutating the original config", func() {
+			base := scorer.NewAzureConfig("azure-key", server.URL, nil)
+			derived := base.WithAzureDeployment(openai.GPT4, "legacy-gpt4")
+
+			Expect(derived.AzureConfig.DeploymentFor(openai.GPT4)).To(Equal("legacy-gpt4"))
+			Expect(base.AzureConfig.Deployments).To(BeEmpty())
+		})
+
+		It("should follow Azure naming for unmapped models", func() {
+			azure := &scorer.AzureConfig{}
+			Expect(azure.DeploymentFor("gpt-3.5-turbo")).To(Equal("gpt-35-turbo"))
+		})
+	})
+
+	Describe("validation", func() {
+		It("should require an endpoint", func() {
+			cfg := scorer.NewAzureConfig("azure-key", "", deployed)
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("azure endpoint is required")))
+		})
+
+		It("should require a deployment for the configured model when a mapping is given", func() {
+			cfg := scorer.NewAzureConfig("azure-key", server.URL, deployed).WithModel(openai.GPT4Turbo)
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("no azure deployment configured")))
+		})
+
+		It("should require AzureConfig for the azure provider", func() {
+			cfg := scorer.NewDefaultConfig("azure-key")
+			cfg.Provider = scorer.ProviderAzure
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("requires AzureConfig")))
+
+			_, err := scorer.NewScorer(cfg)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
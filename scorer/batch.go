@@ -6,17 +6,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/sashabaranov/go-openai/jsonschema"
 )
 
-// processBatch handles the core batch scoring workflow by formatting prompts,
+// scoreBatch handles the core batch scoring workflow by formatting prompts,
 // calling the OpenAI API with JSON schema validation, and mapping responses back to items.
-// This is the primary orchestration function for batch processing operations.
-func (s *scorer) processBatch(ctx context.Context, batch []TextItem, options *scoringOptions) ([]ScoredItem, error) {
+// processBatch calls it again for any items the response left out.
+func (s *scorer) scoreBatch(ctx context.Context, batch []TextItem, options *scoringOptions) ([]ScoredItem, error) {
 	// Determine which prompt to use
 	promptText := s.prompt
 	if options != nil && options.promptText != "" {
@@ -31,117 +34,371 @@ func (s *scorer) processBatch(ctx context.Context, batch []TextItem, options *sc
 
 	slog.Info("Processing batch of text items", "batch_size", len(batch))
 
-	schema, err := jsonschema.GenerateSchemaForType(scoreResponse{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate JSON schema for batch of %d items: %w", len(batch), err)
+	rubric := s.config.resolveRubric(options)
+	if rubric != nil && options != nil && options.logprobs {
+		// Expected scores need a single score per item, so rubrics skip logprobs
+		slog.Debug("Logprobs are not used with rubric scoring")
+		withoutLogprobs := *options
+		withoutLogprobs.logprobs = false
+		options = &withoutLogprobs
+	}
+
+	var schema *jsonschema.Definition
+	if rubric != nil {
+		schema = rubric.schema()
+		prompt = prompt + "\n\n" + rubric.instructions()
+	} else {
+		scale := s.config.resolveScale()
+		schema, err = scale.schema()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate JSON schema for batch of %d items: %w", len(batch), err)
+		}
+	}
+
+	entities := s.config.resolveEntities(options)
+	if entities != nil {
+		schema, err = entities.withEntities(schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate JSON schema for batch of %d items: %w", len(batch), err)
+		}
+		prompt = prompt + "\n\n" + entities.instructions()
+	}
+
+	evidence := s.config.resolveEvidence(options)
+	if evidence != nil {
+		schema = evidence.withEvidence(schema)
+		prompt = prompt + "\n\n" + evidence.instructions()
 	}
 
-	resp, err := s.createChatCompletion(ctx, prompt, schema, options)
+	resp, err := s.completeJSON(ctx, prompt, schema, options, len(batch))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create chat completion for batch of %d items: %w", len(batch), err)
+		return nil, err
 	}
+	content := resp.content
 
-	// Parse response
-	content := resp.Choices[0].Message.Content
+	// Map scores back to items
+	var results []ScoredItem
+	if rubric != nil {
+		var scores rubricResponse
+		if err := json.Unmarshal([]byte(content), &scores); err != nil {
+			return nil, fmt.Errorf("failed to parse response JSON: %w", err)
+		}
+		slog.Info("Received rubric scores from OpenAI", "scores_count", len(scores.Scores))
+		results = s.mapRubricScoresToItems(batch, scores.Scores, rubric)
+	} else {
+		var scores scaledScoreResponse
+		if err := json.Unmarshal([]byte(content), &scores); err != nil {
+			return nil, fmt.Errorf("failed to parse response JSON: %w", err)
+		}
+		slog.Info("Received scores from OpenAI", "scores_count", len(scores.Scores))
+		results = s.mapScoresToItems(batch, scores.Scores)
+		if resp.logprobs != nil {
+			s.applyExpectedScores(results, resp.logprobs, scores.Scores)
+		}
+	}
 
-	slog.Debug("Received response from OpenAI", "content_length", len(content))
+	if entities != nil {
+		if err := s.applyEntities(results, content, entities); err != nil {
+			return nil, err
+		}
+	}
+	if evidence != nil {
+		if err := s.applyEvidence(results, content, evidence); err != nil {
+			return nil, err
+		}
+	}
 
-	var scores scoreResponse
-	if err := json.Unmarshal([]byte(content), &scores); err != nil {
-		slog.Error("Failed to parse response JSON", "error", err, "content", content)
-		return nil, fmt.Errorf("failed to parse response JSON: %w", err)
+	for i := range results {
+		results[i].Backend = resp.backend
 	}
+	return results, nil
+}
 
-	slog.Info("Received scores from OpenAI", "scores_count", len(scores.Scores))
+// jsonResponse is a JSON completion and the backend that produced it
+type jsonResponse struct {
+	content  string           // Response JSON, repaired where needed
+	logprobs *openai.LogProbs // Token logprobs, when requested and returned
+	backend  string           // Backend that answered, as "provider/model"
+}
 
-	// Map scores back to items
-	return s.mapScoresToItems(batch, scores.Scores), nil
+// completeJSON sends a prompt expecting JSON matching schema through this
+// scorer's backend, then through each per-call fallback in turn while the
+// previous backend is unavailable. A per-request model override applies to
+// this scorer's backend only.
+func (s *scorer) completeJSON(ctx context.Context, prompt string, schema *jsonschema.Definition, options *scoringOptions, batchSize int) (jsonResponse, error) {
+	resp, err := s.requestJSON(ctx, prompt, schema, options, batchSize)
+	if err == nil || len(s.fallbacks) == 0 {
+		return resp, err
+	}
+
+	fallbackOptions := scoringOptions{}
+	if options != nil {
+		fallbackOptions = *options
+	}
+	fallbackOptions.model = ""
+
+	backend := backendLabel(s.config.providerOrDefault(), s.config.resolveModel(options))
+	for _, fallback := range s.fallbacks {
+		if !shouldFallback(ctx, err) {
+			return jsonResponse{}, err
+		}
+
+		next := backendLabel(fallback.config.providerOrDefault(), fallback.config.resolveModel(&fallbackOptions))
+		slog.Warn("Backend unavailable, falling back",
+			"backend", backend,
+			"next", next,
+			"error", err)
+		s.metrics.RecordFallback(backend, next)
+
+		resp, err = fallback.requestJSON(ctx, prompt, schema, &fallbackOptions, batchSize)
+		if err == nil {
+			return resp, nil
+		}
+		backend = next
+	}
+
+	return jsonResponse{}, err
 }
 
-// createChatCompletion builds and sends the OpenAI API request with structured JSON response format.
-// It handles model selection precedence: options.model > config.Model > GPT4oMini default.
-func (s *scorer) createChatCompletion(ctx context.Context, prompt string, schema *jsonschema.Definition, options *scoringOptions) (openai.ChatCompletionResponse, error) {
-	// Determine model to use
-	model := s.config.Model
+// requestJSON sends a prompt expecting JSON matching schema and returns the
+// response content and any token logprobs. Content that does not parse is
+// repaired leniently where possible, and otherwise sent back to the model with
+// the parse error, up to the configured number of corrections. Output modes
+// that do not enforce the schema server-side are validated locally before the
+// content is returned.
+func (s *scorer) requestJSON(ctx context.Context, prompt string, schema *jsonschema.Definition, options *scoringOptions, batchSize int) (jsonResponse, error) {
+	maxCorrections := s.config.resolveMaxJSONCorrections()
+	var followUp []openai.ChatCompletionMessage
+
+	for attempt := 0; ; attempt++ {
+		resp, mode, err := s.createChatCompletion(ctx, prompt, schema, options, followUp...)
+		if err != nil {
+			return jsonResponse{}, fmt.Errorf("failed to create chat completion for batch of %d items: %w", batchSize, err)
+		}
+
+		s.recordUsage(resp.Usage)
+
+		choice, err := checkChoices(resp)
+		if err != nil {
+			slog.Warn("Received unusable response", "error", err, "batch_size", batchSize)
+			return jsonResponse{}, fmt.Errorf("unusable response for batch of %d items: %w", batchSize, err)
+		}
+
+		// Parse response
+		content := choice.Message.Content
+
+		slog.Debug("Received response from OpenAI", "content_length", len(content))
+
+		repaired, repairs, err := repairJSON(content)
+		if err != nil {
+			if attempt >= maxCorrections {
+				slog.Error("Failed to parse response JSON", "error", err, "content", content)
+				return jsonResponse{}, fmt.Errorf("failed to parse response JSON: %w", err)
+			}
+			slog.Warn("Response is not valid JSON, asking the model to correct it",
+				"error", err,
+				"attempt", attempt+1)
+			followUp = correctionMessages(content, err)
+			continue
+		}
+
+		for _, kind := range repairs {
+			slog.Info("Repaired response JSON", "repair", kind)
+			s.metrics.RecordJSONRecovery(kind)
+		}
+		if attempt > 0 {
+			slog.Info("Model corrected response JSON", "attempts", attempt)
+			s.metrics.RecordJSONRecovery(recoveryReprompt)
+		}
+		content = repaired
+
+		// Only strict schema mode guarantees the shape, so check everything else locally
+		if mode != OutputModeJSONSchema {
+			if err := validateAgainstSchema(schema, content); err != nil {
+				slog.Error("Response failed schema validation", "error", err, "output_mode", mode, "content", content)
+				return jsonResponse{}, fmt.Errorf("failed to validate response JSON: %w", err)
+			}
+		}
+
+		return jsonResponse{
+			content:  content,
+			logprobs: choice.LogProbs,
+			backend:  backendLabel(s.config.providerOrDefault(), s.config.resolveModel(options)),
+		}, nil
+	}
+}
+
+// resolveModel applies model selection precedence: options.model > config.Model > provider default
+func (c Config) resolveModel(options *scoringOptions) string {
+	model := c.Model
 	if model == "" {
-		model = openai.GPT4oMini
+		model = defaultModel(c.Provider)
 	}
 	if options != nil && options.model != "" {
 		model = options.model
 	}
+	return model
+}
+
+// createChatCompletion builds and sends the OpenAI API request with structured JSON response format.
+// It handles model selection precedence: options.model > config.Model > provider default.
+// In auto output mode a server that rejects the requested response format is retried
+// with the next less demanding mode, and the downgrade is remembered for the model. The mode actually
+// used is returned so the caller knows whether the response needs local validation.
+// Any followUp messages are sent after the prompt, as when asking for a correction.
+func (s *scorer) createChatCompletion(ctx context.Context, prompt string, schema *jsonschema.Definition, options *scoringOptions, followUp ...openai.ChatCompletionMessage) (openai.ChatCompletionResponse, OutputMode, error) {
+	model := s.config.resolveModel(options)
+	mode := s.outputMode(model)
+	params := s.resolveGeneration(options)
+	system := s.system
+	if options != nil && options.systemPrompt != "" {
+		system = options.systemPrompt
+	}
+	request, err := s.buildChatRequest(model, system, prompt, schema, mode, params)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, mode, err
+	}
+	request.Messages = append(request.Messages, followUp...)
+
+	slog.Debug("Sending request to OpenAI", "model", model, "prompt_length", len(prompt), "output_mode", mode)
+
+	resp, err := s.client.CreateChatCompletion(ctx, request)
+	for err != nil && s.negotiatesOutputMode(model) && isResponseFormatRejection(err) {
+		next, ok := downgradeMode(mode)
+		if !ok {
+			break
+		}
+
+		slog.Warn("Server rejected output mode, falling back",
+			"model", model,
+			"from", mode,
+			"to", next,
+			"error", err)
+
+		mode = next
+		s.detectedModes.Store(model, mode)
+
+		request, err = s.buildChatRequest(model, system, prompt, schema, mode, params)
+		if err != nil {
+			return openai.ChatCompletionResponse{}, mode, err
+		}
+		request.Messages = append(request.Messages, followUp...)
+		resp, err = s.client.CreateChatCompletion(ctx, request)
+	}
+
+	return resp, mode, err
+}
+
+// buildChatRequest assembles the chat completion request for the given output mode
+// and translates generation parameters for the model family. JSON and text modes
+// cannot carry a schema, so the schema is described in the system prompt instead.
+func (s *scorer) buildChatRequest(model, system, prompt string, schema *jsonschema.Definition, mode OutputMode, params generationParams) (openai.ChatCompletionRequest, error) {
+	var responseFormat *openai.ChatCompletionResponseFormat
+
+	switch mode {
+	case OutputModeJSONObject, OutputModeText:
+		instructions, err := schemaInstructions(schema)
+		if err != nil {
+			return openai.ChatCompletionRequest{}, err
+		}
+		system = system + "\n\n" + instructions
+		if mode == OutputModeJSONObject {
+			responseFormat = &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+			}
+		}
+	default:
+		responseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "score_response",
+				Strict: true,
+				Schema: schema,
+			},
+		}
+	}
 
 	request := openai.ChatCompletionRequest{
 		Model: model,
 		Messages: []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleSystem,
-				Content: systemPrompt,
+				Content: system,
 			},
 			{
 				Role:    openai.ChatMessageRoleUser,
 				Content: prompt,
 			},
 		},
-		ResponseFormat: &openai.ChatCompletionResponseFormat{
-			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
-			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
-				Name:   "score_response",
-				Strict: true,
-				Schema: schema,
-			},
-		},
+		ResponseFormat: responseFormat,
 	}
+	applyGeneration(&request, params)
+	foldSystemPrompt(&request)
 
-	slog.Debug("Sending request to OpenAI", "model", model, "prompt_length", len(prompt))
-
-	return s.client.CreateChatCompletion(ctx, request)
+	return request, nil
 }
 
 // mapScoresToItems creates the final results by matching API scores to input items by ID.
-// It provides graceful degradation: missing scores default to 0, out-of-range scores are clamped to [0,100].
-func (s *scorer) mapScoresToItems(items []TextItem, scores []scoreItem) []ScoredItem {
-	scoreMap := make(map[string]scoreItem)
-	for _, score := range scores {
-		scoreMap[score.ItemID] = score
-	}
+// Items the response leaves out, or scores more than once, are returned with Missing set,
+// and out-of-range scores are clamped to the scale.
+func (s *scorer) mapScoresToItems(items []TextItem, scores []scaledScoreItem) []ScoredItem {
+	scale := s.config.resolveScale()
+
+	scoreMap := reconcileIDs(items, scores, func(score scaledScoreItem) string { return score.ItemID }, s.metrics)
 
 	results := make([]ScoredItem, len(items))
 	for i, item := range items {
-		if score, found := scoreMap[item.ID]; found {
-			// Validate score range
-			if score.Score < 0 || score.Score > 100 {
-				slog.Warn("Score out of range, clamping to valid range",
-					"item_id", item.ID,
-					"original_score", score.Score)
-				if score.Score < 0 {
-					score.Score = 0
-				} else if score.Score > 100 {
-					score.Score = 100
-				}
-			}
+		score, found := scoreMap[item.ID]
+		if !found {
+			results[i] = unscored(item)
+			continue
+		}
 
-			results[i] = ScoredItem{
-				Item:   item,
-				Score:  score.Score,
-				Reason: score.Reason,
-			}
-			slog.Debug("Mapped score to item",
+		// Validate score range
+		if score.Score < scale.Min || score.Score > scale.Max {
+			slog.Warn("Score out of range, clamping to valid range",
 				"item_id", item.ID,
-				"score", score.Score)
-		} else {
-			slog.Warn("Score not found for item, using default",
-				"item_id", item.ID)
-			results[i] = ScoredItem{
-				Item:   item,
-				Score:  0,
-				Reason: "Score not found in response",
-			}
+				"original_score", score.Score,
+				"scale", scale.String())
 		}
+		value := scale.Clamp(score.Score)
+
+		results[i] = ScoredItem{
+			Item:   item,
+			Score:  int(math.Round(value)),
+			Value:  value,
+			Reason: score.Reason,
+		}
+		slog.Debug("Mapped score to item",
+			"item_id", item.ID,
+			"score", value)
 	}
 
 	return results
 }
 
+// withScoreProperty returns a copy of a scores response schema with a
+// required property added to each score, leaving the original untouched
+func withScoreProperty(schema *jsonschema.Definition, name string, property jsonschema.Definition) *jsonschema.Definition {
+	scores := schema.Properties["scores"]
+	items := *scores.Items
+	items.Properties = make(map[string]jsonschema.Definition, len(scores.Items.Properties)+1)
+	for n, p := range scores.Items.Properties {
+		items.Properties[n] = p
+	}
+	items.Properties[name] = property
+	items.Required = append(append([]string{}, scores.Items.Required...), name)
+	scores.Items = &items
+
+	extended := *schema
+	extended.Properties = make(map[string]jsonschema.Definition, len(schema.Properties))
+	for n, p := range schema.Properties {
+		extended.Properties[n] = p
+	}
+	extended.Properties["scores"] = scores
+	return &extended
+}
+
 // formatPrompt supports multiple prompt formats with automatic detection:
 // Go templates ({{}}), sprintf-style (%s), or plain text with appended items.
 func (s *scorer) formatPrompt(promptText string, items []TextItem, options *scoringOptions) (string, error) {
@@ -152,17 +409,18 @@ func (s *scorer) formatPrompt(promptText string, items []TextItem, options *scor
 
 	// Legacy sprintf-style formatting
 	if strings.Contains(promptText, "%s") {
-		itemsText := s.formatItemsAsText(items)
+		itemsText := formatItemsAsText(items)
 		return fmt.Sprintf(promptText, itemsText), nil
 	}
 
 	// If no placeholders, append items to the prompt
-	itemsText := s.formatItemsAsText(items)
+	itemsText := formatItemsAsText(items)
 	return fmt.Sprintf("%s\n\nItems to score:\n%s", promptText, itemsText), nil
 }
 
 // formatPromptWithTemplate executes Go template syntax with context data.
-// Available template variables: {{.Items}}, {{.Count}}, plus any extraContext fields.
+// Available template variables: {{.Items}}, {{.Count}}, {{.ScaleMin}}, {{.ScaleMax}},
+// plus any extraContext fields.
 func (s *scorer) formatPromptWithTemplate(promptText string, items []TextItem, options *scoringOptions) (string, error) {
 	tmpl, err := template.New("prompt").Parse(promptText)
 	if err != nil {
@@ -170,9 +428,12 @@ func (s *scorer) formatPromptWithTemplate(promptText string, items []TextItem, o
 	}
 
 	// Prepare template data
+	scale := s.config.resolveScale()
 	data := map[string]interface{}{
-		"Items": items,
-		"Count": len(items),
+		"Items":    items,
+		"Count":    len(items),
+		"ScaleMin": scale.Min,
+		"ScaleMax": scale.Max,
 	}
 
 	// Add extra context if provided
@@ -195,18 +456,112 @@ func (s *scorer) formatPromptWithTemplate(promptText string, items []TextItem, o
 	return buf.String(), nil
 }
 
-func (s *scorer) formatItemsAsText(items []TextItem) string {
+func formatItemsAsText(items []TextItem) string {
 	var sb strings.Builder
 	for i, item := range items {
 		sb.WriteString(fmt.Sprintf("Item %d (ID: %s):\n", i+1, item.ID))
 		sb.WriteString(item.Content)
 		if item.Metadata != nil && len(item.Metadata) > 0 {
+			// Sort keys so identical items always produce identical prompts
+			keys := make([]string, 0, len(item.Metadata))
+			for k := range item.Metadata {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
 			sb.WriteString("\nMetadata: ")
-			for k, v := range item.Metadata {
-				sb.WriteString(fmt.Sprintf("%s=%v ", k, v))
+			for _, k := range keys {
+				sb.WriteString(fmt.Sprintf("%s=%v ", k, item.Metadata[k]))
 			}
 		}
 		sb.WriteString("\n\n")
 	}
 	return sb.String()
 }
+
+// runAll calls fn for indices 0..n-1 with at most maxConcurrent in flight, in
+// index order when maxConcurrent is one, and returns each call's value and
+// error in index order. A failed call does not cancel the others; calls that
+// have not started when the context is done fail with its error.
+func runAll[T any](ctx context.Context, n, maxConcurrent int, fn func(ctx context.Context, i int) (T, error)) ([]T, []error) {
+	values := make([]T, n)
+	errs := make([]error, n)
+
+	if maxConcurrent <= 1 {
+		for i := 0; i < n; i++ {
+			if errs[i] = ctx.Err(); errs[i] == nil {
+				values[i], errs[i] = fn(ctx, i)
+			}
+		}
+		return values, errs
+	}
+
+	sem := make(chan struct{}, maxConcurrent)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			sem <- struct{}{}        // Acquire semaphore
+			defer func() { <-sem }() // Release semaphore
+
+			if errs[index] = ctx.Err(); errs[index] == nil {
+				values[index], errs[index] = fn(ctx, index)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return values, errs
+}
+
+// runConcurrently calls fn for indices 0..n-1 with at most maxConcurrent in
+// flight and returns the results in index order. The first error cancels the
+// remaining calls.
+func runConcurrently[T any](ctx context.Context, n, maxConcurrent int, fn func(ctx context.Context, i int) (T, error)) ([]T, error) {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		index int
+		value T
+		err   error
+	}
+
+	sem := make(chan struct{}, maxConcurrent)
+	results := make(chan result, n)
+
+	for i := 0; i < n; i++ {
+		go func(index int) {
+			sem <- struct{}{}        // Acquire semaphore
+			defer func() { <-sem }() // Release semaphore
+
+			if err := ctx.Err(); err != nil {
+				results <- result{index: index, err: err}
+				return
+			}
+			value, err := fn(ctx, index)
+			results <- result{index: index, value: value, err: err}
+		}(i)
+	}
+
+	values := make([]T, n)
+	var firstErr error
+	for i := 0; i < n; i++ {
+		r := <-results
+		if r.err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("processing batch %d: %w", r.index, r.err)
+			cancel()
+		}
+		values[r.index] = r.value
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return values, nil
+}
@@ -0,0 +1,375 @@
+package scorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+var classifySystemPrompt string
+var classifyPrompt string
+var classifyPromptError error
+
+func init() {
+	systemBytes, err := promptFS.ReadFile("prompts/classify_system_prompt.txt")
+	if err != nil {
+		classifyPromptError = fmt.Errorf("failed to load classify system prompt: %w", err)
+		return
+	}
+	classifySystemPrompt = string(systemBytes)
+
+	promptBytes, err := promptFS.ReadFile("prompts/classify_prompt.txt")
+	if err != nil {
+		classifyPromptError = fmt.Errorf("failed to load classify prompt: %w", err)
+		return
+	}
+	classifyPrompt = string(promptBytes)
+}
+
+// Classifier assigns text items labels from a caller-defined set
+type Classifier interface {
+	// Classify labels every item, returning results in input order. When some
+	// batches fail, the results are still returned alongside the error, with
+	// the failed batches' items Missing.
+	Classify(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ClassifiedItem, error)
+}
+
+// Label is one class a classifier can assign
+type Label struct {
+	Name        string // Label value returned in results
+	Description string // What the label means, shown to the model
+}
+
+// LabelSet defines the labels a classifier chooses from
+type LabelSet struct {
+	Labels     []Label
+	MultiLabel bool // Allow any number of labels per item; otherwise exactly one
+	Confidence bool // Ask the model for a 0-1 confidence with each label
+}
+
+// LabelResult is one label assigned to an item
+type LabelResult struct {
+	Label      string  // Label name from the LabelSet
+	Reason     string  // AI explanation for the label
+	Confidence float64 // Confidence from 0 to 1; 0 unless LabelSet.Confidence is set
+}
+
+// ClassifiedItem is a text item with the labels assigned to it
+type ClassifiedItem struct {
+	Item    TextItem      // Original text item
+	Labels  []LabelResult // Assigned labels; empty when the item was missing from the response
+	Backend string        // Backend that produced the labels, as "provider/model"
+	Missing bool          // True when the item was not classified, even after re-asking
+	Err     error         // Why a Missing item was not classified: ErrItemMissing, a *RefusalError or a *BatchError
+}
+
+// HasLabel reports whether the item was assigned the named label
+func (c ClassifiedItem) HasLabel(name string) bool {
+	for _, label := range c.Labels {
+		if label.Label == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Internal response types for classification JSON parsing
+type classifyResponse struct {
+	Version         string               `json:"version"`
+	Classifications []classificationItem `json:"classifications"`
+}
+
+type classificationItem struct {
+	ItemID string            `json:"item_id"`
+	Labels []classifiedLabel `json:"labels"`
+}
+
+type classifiedLabel struct {
+	Label      string  `json:"label"`
+	Reason     string  `json:"reason"`
+	Confidence float64 `json:"confidence"`
+}
+
+// classifier batches items through a scorer's client, prompts and output mode handling
+type classifier struct {
+	scorer *scorer
+	labels LabelSet
+}
+
+// NewClassifier creates a classifier for a label set. Items are batched and
+// validated as for scoring, and the response schema restricts labels to the
+// set with an enum, so strict schema mode cannot return unknown labels.
+// Batches run concurrently up to Config.MaxConcurrent without a failed batch
+// cancelling the others, are split when the response is truncated or
+// refused, and the config's retry and circuit breaker settings apply to every
+// call.
+func NewClassifier(cfg Config, labels LabelSet) (Classifier, error) {
+	if classifyPromptError != nil {
+		return nil, classifyPromptError
+	}
+
+	if err := labels.Validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := newPerCallScorer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &classifier{scorer: s, labels: labels}, nil
+}
+
+// Validate checks that the label set is usable
+func (l LabelSet) Validate() error {
+	if len(l.Labels) == 0 {
+		return errors.New("label set must have at least one label")
+	}
+
+	seen := make(map[string]bool, len(l.Labels))
+	for _, label := range l.Labels {
+		if strings.TrimSpace(label.Name) == "" {
+			return errors.New("label name cannot be empty")
+		}
+		if seen[label.Name] {
+			return fmt.Errorf("duplicate label: %s", label.Name)
+		}
+		seen[label.Name] = true
+	}
+
+	return nil
+}
+
+// Classify implements Classifier
+func (c *classifier) Classify(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ClassifiedItem, error) {
+	if items == nil {
+		return nil, errors.New("items cannot be nil")
+	}
+
+	if len(items) == 0 {
+		return []ClassifiedItem{}, nil
+	}
+
+	if err := c.scorer.validateItems(items); err != nil {
+		return nil, err
+	}
+
+	options := &scoringOptions{
+		model:        c.scorer.config.Model,
+		promptText:   classifyPrompt,
+		systemPrompt: classifySystemPrompt,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if err := c.scorer.config.validateOptions(options); err != nil {
+		return nil, err
+	}
+
+	budget := c.scorer.config.newBatchBudget(options, []string{options.systemPrompt, options.promptText}, classifyOutputTokens)
+	batches := budget.split(items)
+
+	outcomes, errs := runAll(ctx, len(batches), c.scorer.config.MaxConcurrent, func(ctx context.Context, i int) ([]ClassifiedItem, error) {
+		return c.classifyBatch(ctx, batches[i], options)
+	})
+	classified, err := collectBatches(batches, outcomes, errs, func(item TextItem, batchErr *BatchError) ClassifiedItem {
+		return ClassifiedItem{Item: item, Missing: true, Err: batchErr}
+	})
+
+	slog.Info("Completed classification",
+		"total_batches", len(batches),
+		"total_items", len(classified))
+
+	// Items of failed batches are Missing, and err joins their batch errors
+	return classified, err
+}
+
+// classifyBatch classifies one batch, splitting it when the response is
+// truncated or refused, then re-asks for the items missing from the response
+// up to the configured re-ask limit
+func (c *classifier) classifyBatch(ctx context.Context, batch []TextItem, options *scoringOptions) ([]ClassifiedItem, error) {
+	results, err := c.labelSplitting(ctx, batch, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return reaskMissing(ctx, c.scorer, results, func(result ClassifiedItem) bool {
+		return errors.Is(result.Err, ErrItemMissing)
+	}, func(result ClassifiedItem) TextItem {
+		return result.Item
+	}, func(ctx context.Context, missing []TextItem) ([]ClassifiedItem, error) {
+		return c.labelSplitting(ctx, missing, options)
+	})
+}
+
+// labelSplitting requests labels for a batch through splitUnusable, leaving a
+// refused item unlabelled with a *RefusalError
+func (c *classifier) labelSplitting(ctx context.Context, batch []TextItem, options *scoringOptions) ([]ClassifiedItem, error) {
+	return splitUnusable(ctx, c.scorer, batch, func(ctx context.Context, batch []TextItem) ([]ClassifiedItem, error) {
+		return c.requestLabels(ctx, batch, options)
+	}, func(item TextItem, refusal *RefusalError) ClassifiedItem {
+		return ClassifiedItem{Item: item, Missing: true, Err: refusal}
+	})
+}
+
+// requestLabels sends one classification request and maps the labels back to
+// the batch's items
+func (c *classifier) requestLabels(ctx context.Context, batch []TextItem, options *scoringOptions) ([]ClassifiedItem, error) {
+	prompt, err := c.scorer.formatPrompt(options.promptText, batch, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format prompt: %w", err)
+	}
+	prompt = prompt + "\n\n" + c.labels.instructions()
+
+	slog.Info("Classifying batch of text items", "batch_size", len(batch))
+
+	resp, err := c.scorer.completeJSON(ctx, prompt, c.labels.schema(), options, len(batch))
+	if err != nil {
+		return nil, err
+	}
+
+	var response classifyResponse
+	if err := json.Unmarshal([]byte(resp.content), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response JSON: %w", err)
+	}
+	slog.Info("Received classifications from OpenAI", "classifications_count", len(response.Classifications))
+
+	results := c.mapLabelsToItems(batch, response.Classifications)
+
+	for i := range results {
+		results[i].Backend = resp.backend
+	}
+	return results, nil
+}
+
+// mapLabelsToItems matches classifications to input items by ID through
+// reconcileIDs, marking items without exactly one classification as missing.
+// Labels outside the set and repeated labels are dropped, confidences are
+// clamped to [0,1], and single-label sets keep only the first label.
+func (c *classifier) mapLabelsToItems(items []TextItem, classifications []classificationItem) []ClassifiedItem {
+	known := make(map[string]bool, len(c.labels.Labels))
+	for _, label := range c.labels.Labels {
+		known[label.Name] = true
+	}
+
+	classificationMap := reconcileIDs(items, classifications, func(classification classificationItem) string {
+		return classification.ItemID
+	}, c.scorer.metrics)
+
+	results := make([]ClassifiedItem, len(items))
+	for i, item := range items {
+		results[i] = ClassifiedItem{Item: item}
+
+		classification, found := classificationMap[item.ID]
+		if !found {
+			slog.Warn("Classification not found for item", "item_id", item.ID)
+			results[i].Missing = true
+			results[i].Err = ErrItemMissing
+			continue
+		}
+
+		used := make(map[string]bool, len(classification.Labels))
+		for _, label := range classification.Labels {
+			if !known[label.Label] || used[label.Label] {
+				slog.Warn("Ignoring unknown or repeated label",
+					"item_id", item.ID,
+					"label", label.Label)
+				continue
+			}
+			used[label.Label] = true
+
+			result := LabelResult{Label: label.Label, Reason: label.Reason}
+			if c.labels.Confidence {
+				result.Confidence = math.Min(math.Max(label.Confidence, 0), 1)
+			}
+			results[i].Labels = append(results[i].Labels, result)
+		}
+
+		if !c.labels.MultiLabel && len(results[i].Labels) > 1 {
+			slog.Warn("Multiple labels for single-label set, keeping the first",
+				"item_id", item.ID,
+				"labels", len(results[i].Labels))
+			results[i].Labels = results[i].Labels[:1]
+		}
+	}
+
+	return results
+}
+
+// schema builds the response schema with the label names as an enum
+func (l LabelSet) schema() *jsonschema.Definition {
+	names := make([]string, len(l.Labels))
+	for i, label := range l.Labels {
+		names[i] = label.Name
+	}
+
+	labelProps := map[string]jsonschema.Definition{
+		"label":  {Type: jsonschema.String, Enum: names},
+		"reason": {Type: jsonschema.String},
+	}
+	labelRequired := []string{"label", "reason"}
+	if l.Confidence {
+		labelProps["confidence"] = jsonschema.Definition{
+			Type:        jsonschema.Number,
+			Description: "Confidence from 0 to 1",
+		}
+		labelRequired = append(labelRequired, "confidence")
+	}
+
+	return &jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"version": {Type: jsonschema.String},
+			"classifications": {
+				Type: jsonschema.Array,
+				Items: &jsonschema.Definition{
+					Type: jsonschema.Object,
+					Properties: map[string]jsonschema.Definition{
+						"item_id": {Type: jsonschema.String},
+						"labels": {
+							Type: jsonschema.Array,
+							Items: &jsonschema.Definition{
+								Type:                 jsonschema.Object,
+								Properties:           labelProps,
+								Required:             labelRequired,
+								AdditionalProperties: false,
+							},
+						},
+					},
+					Required:             []string{"item_id", "labels"},
+					AdditionalProperties: false,
+				},
+			},
+		},
+		Required:             []string{"version", "classifications"},
+		AdditionalProperties: false,
+	}
+}
+
+// instructions describes the label set to the model
+func (l LabelSet) instructions() string {
+	var sb strings.Builder
+	if l.MultiLabel {
+		sb.WriteString("Assign every item each label below that applies, or none if no label applies")
+	} else {
+		sb.WriteString("Assign every item exactly one label from the set below")
+	}
+	if l.Confidence {
+		sb.WriteString(", with a confidence from 0 to 1 for each label")
+	}
+	sb.WriteString(":\n")
+	for _, label := range l.Labels {
+		sb.WriteString("- " + label.Name)
+		if label.Description != "" {
+			sb.WriteString(": " + label.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
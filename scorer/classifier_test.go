@@ -0,0 +1,267 @@
+// Package scorer_test covers label classification: the enum response schema,
+// single- and multi-label mapping, confidences, re-asking for missing items,
+// keeping the batches that succeed, and label set validation.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Classifier", func() {
+	var (
+		ctx    context.Context
+		labels scorer.LabelSet
+		items  []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		labels = scorer.LabelSet{Labels: []scorer.Label{
+			{Name: "event", Description: "A dated happening such as a gig or market"},
+			{Name: "venue", Description: "A place such as a bar or gallery"},
+			{Name: "other"},
+		}}
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Jazz trio at the Blue Note, Friday 8pm"},
+			{ID: "2", Content: "Anyone know a good plumber?"},
+		}
+	})
+
+	It("should restrict labels to the set with a schema enum", func() {
+		fake := scorertest.NewFakeClient()
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: fake}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := fake.Requests()[0]
+		Expect(req.ResponseFormat.JSONSchema.Strict).To(BeTrue())
+		schema, err := json.Marshal(req.ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).To(ContainSubstring(`"enum":["event","venue","other"]`))
+		Expect(string(schema)).ToNot(ContainSubstring("confidence"))
+
+		Expect(req.Messages[1].Content).To(ContainSubstring("Text items to classify"))
+		Expect(req.Messages[1].Content).To(ContainSubstring("exactly one label"))
+		Expect(req.Messages[1].Content).To(ContainSubstring("- venue: A place such as a bar or gallery"))
+	})
+
+	It("should map labels back to items by ID", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithLabels(map[string][]string{
+			"1": {"event"},
+			"2": {"other"},
+		}))
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: fake, Model: "gpt-4o-mini"}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].Item.ID).To(Equal("1"))
+		Expect(results[0].Labels).To(HaveLen(1))
+		Expect(results[0].Labels[0].Label).To(Equal("event"))
+		Expect(results[0].Labels[0].Reason).ToNot(BeEmpty())
+		Expect(results[0].HasLabel("event")).To(BeTrue())
+		Expect(results[0].Backend).To(Equal("openai/gpt-4o-mini"))
+
+		Expect(results[1].HasLabel("other")).To(BeTrue())
+		Expect(results[1].HasLabel("event")).To(BeFalse())
+	})
+
+	It("should keep several labels in multi-label mode with confidences", func() {
+		labels.MultiLabel = true
+		labels.Confidence = true
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","classifications":[
+					{"item_id":"1","labels":[
+						{"label":"event","reason":"gig on Friday","confidence":0.9},
+						{"label":"venue","reason":"names the Blue Note","confidence":1.7}]},
+					{"item_id":"2","labels":[]}]}`
+			},
+		}
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: client}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(results[0].Labels).To(Equal([]scorer.LabelResult{
+			{Label: "event", Reason: "gig on Friday", Confidence: 0.9},
+			{Label: "venue", Reason: "names the Blue Note", Confidence: 1},
+		}))
+		Expect(results[1].Labels).To(BeEmpty())
+
+		Expect(client.requests[0].Messages[1].Content).To(ContainSubstring("each label below that applies"))
+	})
+
+	It("should drop unknown and repeated labels and keep one label in single-label mode", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","classifications":[
+					{"item_id":"1","labels":[
+						{"label":"party","reason":"not in the set","confidence":0},
+						{"label":"event","reason":"gig","confidence":0},
+						{"label":"event","reason":"again","confidence":0},
+						{"label":"venue","reason":"club","confidence":0}]}]}`
+			},
+		}
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: client, OutputMode: scorer.OutputModeJSONObject}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(results[0].Labels).To(Equal([]scorer.LabelResult{{Label: "event", Reason: "gig"}}))
+		Expect(results[1].Labels).To(BeEmpty())
+	})
+
+	It("should batch large inputs and keep input order", func() {
+		items = nil
+		scripted := make(map[string][]string)
+		for i := 0; i < 25; i++ {
+			id := fmt.Sprintf("item-%d", i)
+			items = append(items, scorer.TextItem{ID: id, Content: fmt.Sprintf("Post number %d", i)})
+			scripted[id] = []string{labels.Labels[i%3].Name}
+		}
+		fake := scorertest.NewFakeClient(scorertest.WithLabels(scripted))
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: fake, MaxConcurrent: 3}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items, scorer.WithTokenBudget(1000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(BeNumerically(">", 1))
+		Expect(results).To(HaveLen(25))
+		for i, result := range results {
+			Expect(result.Item.ID).To(Equal(items[i].ID))
+			Expect(result.HasLabel(labels.Labels[i%3].Name)).To(BeTrue())
+		}
+	})
+
+	It("should re-ask for duplicated IDs and ignore unknown ones", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				if containsID(req, "2") {
+					return `{"version":"1.0","classifications":[
+						{"item_id":"1","labels":[{"label":"event","reason":"gig","confidence":0}]},
+						{"item_id":"1","labels":[{"label":"other","reason":"second answer","confidence":0}]},
+						{"item_id":"9","labels":[{"label":"venue","reason":"not in the batch","confidence":0}]},
+						{"item_id":"2","labels":[{"label":"other","reason":"plumbing","confidence":0}]}]}`
+				}
+				return `{"version":"1.0","classifications":[
+					{"item_id":"1","labels":[{"label":"event","reason":"re-asked","confidence":0}]}]}`
+			},
+		}
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: client}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests).To(HaveLen(2))
+		Expect(results[0].Labels).To(HaveLen(1))
+		Expect(results[0].Labels[0].Reason).To(Equal("re-asked"))
+		Expect(results[0].Missing).To(BeFalse())
+		Expect(results[1].HasLabel("other")).To(BeTrue())
+	})
+
+	It("should mark items the model never classifies as missing", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","classifications":[
+					{"item_id":"2","labels":[{"label":"other","reason":"plumbing","confidence":0}]}]}`
+			},
+		}
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: client}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests).To(HaveLen(1 + scorer.DefaultMaxReasks))
+		Expect(results[0].Missing).To(BeTrue())
+		Expect(results[0].Err).To(MatchError(scorer.ErrItemMissing))
+		Expect(results[0].Labels).To(BeEmpty())
+		Expect(results[1].Missing).To(BeFalse())
+		Expect(results[1].Err).ToNot(HaveOccurred())
+	})
+
+	It("should return batch errors", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{Status: 400, Message: "bad request"})
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: fake}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).To(MatchError(ContainSubstring("bad request")))
+		Expect(results).To(HaveLen(2))
+		for _, result := range results {
+			Expect(result.Missing).To(BeTrue())
+			Expect(result.Err).To(MatchError(scorer.ErrBatchFailed))
+		}
+	})
+
+	It("should keep the labels of batches that succeed when others fail", func() {
+		items = nil
+		for i := 0; i < 20; i++ {
+			items = append(items, scorer.TextItem{ID: fmt.Sprintf("%d", i+1), Content: fmt.Sprintf("Post %d", i+1)})
+		}
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 400, Message: "bad request"})
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: fake, MaxConcurrent: 3}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items, scorer.WithTokenBudget(1000))
+		Expect(err).To(MatchError(scorer.ErrBatchFailed))
+		Expect(err).To(MatchError(ContainSubstring("bad request")))
+		Expect(results).To(HaveLen(20))
+
+		missing := 0
+		for i, result := range results {
+			Expect(result.Item).To(Equal(items[i]))
+			if result.Missing {
+				missing++
+				Expect(result.Labels).To(BeEmpty())
+				Expect(result.Err).To(MatchError(scorer.ErrBatchFailed))
+				continue
+			}
+			Expect(result.Labels).ToNot(BeEmpty())
+		}
+		Expect(missing).To(BeNumerically(">", 0))
+		Expect(missing).To(BeNumerically("<", 20))
+	})
+
+	It("should validate items like scoring does", func() {
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: scorertest.NewFakeClient()}, labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = classifier.Classify(ctx, nil)
+		Expect(err).To(MatchError("items cannot be nil"))
+
+		results, err := classifier.Classify(ctx, []scorer.TextItem{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(BeEmpty())
+
+		_, err = classifier.Classify(ctx, []scorer.TextItem{{ID: "", Content: "text"}})
+		Expect(err).To(MatchError(ContainSubstring("empty ID")))
+	})
+
+	DescribeTable("label set validation",
+		func(set scorer.LabelSet, message string) {
+			_, err := scorer.NewClassifier(scorer.Config{Client: scorertest.NewFakeClient()}, set)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("no labels", scorer.LabelSet{}, "at least one label"),
+		Entry("empty name", scorer.LabelSet{Labels: []scorer.Label{{Name: " "}}}, "label name cannot be empty"),
+		Entry("duplicate name", scorer.LabelSet{Labels: []scorer.Label{{Name: "a"}, {Name: "a"}}}, "duplicate label: a"),
+	)
+})
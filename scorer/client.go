@@ -0,0 +1,104 @@
+package scorer
+
+import (
+	"net/http"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// projectHeader is the header OpenAI uses to scope requests to a project
+const projectHeader = "OpenAI-Project"
+
+// newClient returns the OpenAIClient a scorer should use for the given config.
+// A caller-supplied Config.Client always wins; otherwise a client for the
+// configured provider is built from the API key and the optional base URL,
+// organization, project and HTTP client settings.
+func newClient(cfg Config) OpenAIClient {
+	if cfg.Client != nil {
+		return cfg.Client
+	}
+
+	if cfg.Provider == ProviderAnthropic {
+		return NewAnthropicClient(AnthropicConfig{
+			APIKey:     cfg.APIKey,
+			BaseURL:    cfg.BaseURL,
+			HTTPClient: cfg.HTTPClient,
+		})
+	}
+
+	if cfg.Provider == ProviderAzure {
+		return newAzureClient(cfg)
+	}
+
+	clientConfig := openai.DefaultConfig(cfg.APIKey)
+	if cfg.Provider == ProviderLocal {
+		clientConfig.BaseURL = DefaultLocalBaseURL
+	}
+	if cfg.BaseURL != "" {
+		clientConfig.BaseURL = cfg.BaseURL
+	}
+	clientConfig.OrgID = cfg.OrgID
+	clientConfig.HTTPClient = newHTTPDoer(cfg.HTTPClient, cfg.ProjectID)
+	if cfg.OutputMode == OutputModeGrammar {
+		clientConfig.HTTPClient = &grammarDoer{doer: clientConfig.HTTPClient}
+	}
+
+	return openai.NewClientWithConfig(clientConfig)
+}
+
+// newHTTPDoer returns the HTTP client used for API calls, adding the project
+// header when a project ID is configured.
+func newHTTPDoer(httpClient *http.Client, projectID string) openai.HTTPDoer {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	if projectID == "" {
+		return httpClient
+	}
+	return &headerDoer{
+		doer:    httpClient,
+		headers: map[string]string{projectHeader: projectID},
+	}
+}
+
+// headerDoer sets fixed headers on every outgoing request
+type headerDoer struct {
+	doer    openai.HTTPDoer
+	headers map[string]string
+}
+
+// Do adds the configured headers and delegates to the wrapped doer
+func (d *headerDoer) Do(req *http.Request) (*http.Response, error) {
+	for k, v := range d.headers {
+		req.Header.Set(k, v)
+	}
+	return d.doer.Do(req)
+}
+
+// defaultModel returns the model used when none is configured for the provider.
+// Local servers have no universal default, so they return an empty string.
+func defaultModel(provider Provider) string {
+	switch provider {
+	case ProviderAnthropic:
+		return DefaultAnthropicModel
+	case ProviderLocal:
+		return ""
+	default:
+		return openai.GPT4oMini
+	}
+}
+
+// requiresAPIKey reports whether the provider needs an API key when no client is injected
+func requiresAPIKey(cfg Config) bool {
+	return cfg.Client == nil && cfg.Provider != ProviderLocal
+}
+
+// isValidProvider checks if the provider is supported
+func isValidProvider(provider Provider) bool {
+	switch provider {
+	case "", ProviderOpenAI, ProviderAnthropic, ProviderLocal, ProviderAzure:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+// Package scorer_test covers client construction: injected clients, base URL
+// overrides, organization/project headers and custom HTTP clients. The HTTP
+// tests run against a local httptest server so no network access is needed.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Live jazz at the Blue Note tonight"},
+		}
+	})
+
+	Describe("injected client", func() {
+		It("should use the supplied client without an API key", func() {
+			client := &mockScoringClient{
+				respond: func(req openai.ChatCompletionRequest) string {
+					return `{"version":"1.0","scores":[{"item_id":"1","score":88,"reason":"venue"}]}`
+				},
+			}
+
+			s, err := scorer.NewScorer(scorer.Config{Client: client})
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, items)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Score).To(Equal(88))
+			Expect(client.requests).To(HaveLen(1))
+		})
+
+		It("should pass validation without an API key", func() {
+			cfg := scorer.Config{}.WithClient(&mockScoringClient{})
+			Expect(cfg.Validate()).To(Succeed())
+		})
+	})
+
+	Describe("HTTP client settings", func() {
+		var (
+			server  *httptest.Server
+			mu      sync.Mutex
+			headers http.Header
+			path    string
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				headers = r.Header.Clone()
+				path = r.URL.Path
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+					Choices: []openai.ChatCompletionChoice{
+						{Message: openai.ChatCompletionMessage{
+							Role:    openai.ChatMessageRoleAssistant,
+							Content: `{"version":"1.0","scores":[{"item_id":"1","score":70,"reason":"event"}]}`,
+						}},
+					},
+				})
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should send requests to the configured base URL with org and project headers", func() {
+			cfg := scorer.NewDefaultConfig("test-key").
+				WithBaseURL(server.URL+"/v1").
+				WithOrganization("org-123", "proj-456").
+				WithHTTPClient(server.Client())
+
+			s, err := scorer.NewScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, items)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Score).To(Equal(70))
+
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(path).To(Equal("/v1/chat/completions"))
+			Expect(headers.Get("Authorization")).To(Equal("Bearer test-key"))
+			Expect(headers.Get("OpenAI-Organization")).To(Equal("org-123"))
+			Expect(headers.Get("OpenAI-Project")).To(Equal("proj-456"))
+		})
+
+		It("should reject an invalid base URL", func() {
+			cfg := scorer.NewDefaultConfig("test-key").WithBaseURL("not a url")
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("invalid base URL")))
+		})
+	})
+})
+
+// mockScoringClient records every request and answers with content produced by
+// the respond callback, letting tests drive the real scorer end to end.
+type mockScoringClient struct {
+	mu       sync.Mutex
+	requests []openai.ChatCompletionRequest
+	respond  func(req openai.ChatCompletionRequest) string
+	usage    openai.Usage
+	logprobs func(content string) *openai.LogProbs
+	err      error
+}
+
+// CreateChatCompletion records the request and returns the scripted content or error
+func (m *mockScoringClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	m.mu.Lock()
+	m.requests = append(m.requests, req)
+	m.mu.Unlock()
+
+	if m.err != nil {
+		return openai.ChatCompletionResponse{}, m.err
+	}
+
+	content := `{"version":"1.0","scores":[]}`
+	if m.respond != nil {
+		content = m.respond(req)
+	}
+
+	var logprobs *openai.LogProbs
+	if m.logprobs != nil {
+		logprobs = m.logprobs(content)
+	}
+
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Message: openai.ChatCompletionMessage{
+					Role:    openai.ChatMessageRoleAssistant,
+					Content: content,
+				},
+				LogProbs: logprobs,
+			},
+		},
+		Usage: m.usage,
+	}, nil
+}
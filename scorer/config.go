@@ -3,6 +3,8 @@ package scorer
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 	"text/template"
 	"time"
@@ -25,6 +27,14 @@ func NewDefaultConfig(apiKey string) Config {
 	}
 }
 
+// NewAnthropicConfig creates a config with sensible defaults for the Anthropic Messages API
+func NewAnthropicConfig(apiKey string) Config {
+	cfg := NewDefaultConfig(apiKey)
+	cfg.Provider = ProviderAnthropic
+	cfg.Model = DefaultAnthropicModel
+	return cfg
+}
+
 // NewProductionConfig creates a production-ready config with all resilience features
 func NewProductionConfig(apiKey string) Config {
 	cfg := NewDefaultConfig(apiKey)
@@ -101,6 +111,91 @@ func (c Config) WithModel(model string) Config {
 	return c
 }
 
+// WithClient uses a pre-built OpenAI client instead of constructing one.
+// Useful for tests and for clients that need bespoke construction.
+func (c Config) WithClient(client OpenAIClient) Config {
+	c.Client = client
+	return c
+}
+
+// WithBaseURL points the scorer at an alternative API endpoint such as an
+// internal gateway or an OpenAI-compatible server
+func (c Config) WithBaseURL(baseURL string) Config {
+	c.BaseURL = baseURL
+	return c
+}
+
+// WithOrganization sets the OpenAI organization and project headers
+func (c Config) WithOrganization(orgID, projectID string) Config {
+	c.OrgID = orgID
+	c.ProjectID = projectID
+	return c
+}
+
+// WithHTTPClient sets the HTTP client used for API calls
+func (c Config) WithHTTPClient(httpClient *http.Client) Config {
+	c.HTTPClient = httpClient
+	return c
+}
+
+// WithTemperature sets the default sampling temperature. Unlike leaving
+// Temperature nil, zero is sent to the model and requests greedy decoding.
+func (c Config) WithTemperature(temperature float32) Config {
+	c.Temperature = &temperature
+	return c
+}
+
+// WithGeneration sets the default generation parameters. They are translated
+// per model family, so reasoning models receive max_completion_tokens and
+// reasoning_effort while other models receive max_tokens and temperature. A
+// zero temperature leaves the model default; use WithTemperature(0) for
+// greedy decoding.
+func (c Config) WithGeneration(temperature float32, maxOutputTokens int, effort ReasoningEffort) Config {
+	if temperature != 0 {
+		c = c.WithTemperature(temperature)
+	}
+	c.MaxOutputTokens = maxOutputTokens
+	c.ReasoningEffort = effort
+	return c
+}
+
+// WithFallback appends a backend to try when the configured backends are
+// unavailable. The fallback config should enable its own retry and circuit breaker.
+func (c Config) WithFallback(fallback Config) Config {
+	c.Fallbacks = append(append([]Config{}, c.Fallbacks...), fallback)
+	return c
+}
+
+// WithRubric sets the default rubric for multi-criteria scoring
+func (c Config) WithRubric(rubric Rubric) Config {
+	c.Rubric = &rubric
+	return c
+}
+
+// WithScale sets the scale scores are given on, e.g. LikertScale for 1-5
+func (c Config) WithScale(scale ScoreScale) Config {
+	c.Scale = &scale
+	return c
+}
+
+// WithLogprobs requests token logprobs so results carry an ExpectedScore and Confidence
+func (c Config) WithLogprobs() Config {
+	c.Logprobs = true
+	return c
+}
+
+// WithEntities extracts venues, locations, event dates and prices with every score
+func (c Config) WithEntities(cfg EntityConfig) Config {
+	c.Entities = &cfg
+	return c
+}
+
+// WithEvidence asks for quotes supporting every score, verified against the item content
+func (c Config) WithEvidence(cfg EvidenceConfig) Config {
+	c.Evidence = &cfg
+	return c
+}
+
 // WithTimeout sets the request timeout
 func (c Config) WithTimeout(timeout time.Duration) Config {
 	if timeout < 0 {
@@ -119,6 +214,31 @@ func (c Config) WithMaxConcurrent(max int) Config {
 	return c
 }
 
+// WithBatchTokenBudget sets the estimated tokens per request that items are
+// batched against, covering the prompt, the items and the expected output
+func (c Config) WithBatchTokenBudget(tokens int) Config {
+	if tokens < 0 {
+		panic("BatchTokenBudget must be non-negative")
+	}
+	c.BatchTokenBudget = tokens
+	return c
+}
+
+// WithMaxReasks sets how many follow-up requests are sent for items missing
+// from a response; a negative limit turns re-asking off
+func (c Config) WithMaxReasks(max int) Config {
+	c.MaxReasks = max
+	return c
+}
+
+// WithMaxJSONCorrections sets how many follow-up requests ask the model to
+// correct a response that is not valid JSON after lenient repair fails; a
+// negative limit turns corrections off
+func (c Config) WithMaxJSONCorrections(max int) Config {
+	c.MaxJSONCorrections = max
+	return c
+}
+
 // WithPromptTemplate sets a custom prompt template
 func (c Config) WithPromptTemplate(templateText string) Config {
 	// Validate template syntax
@@ -133,13 +253,41 @@ func (c Config) WithPromptTemplate(templateText string) Config {
 // Validate checks if the config is valid
 func (c Config) Validate() error {
 	// Required fields
-	if c.APIKey == "" {
+	if c.APIKey == "" && requiresAPIKey(c) {
 		return errors.New("API key is required")
 	}
 
-	// Model validation
-	if c.Model != "" && !isValidModel(c.Model) {
-		return fmt.Errorf("unsupported model: %s", c.Model)
+	// Base URL validation
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL: %s", c.BaseURL)
+		}
+	}
+
+	// Provider validation
+	if !isValidProvider(c.Provider) {
+		return fmt.Errorf("unsupported provider: %s", c.Provider)
+	}
+
+	if c.Provider == ProviderLocal && c.Model == "" {
+		return errors.New("model is required for the local provider")
+	}
+
+	if !isValidOutputMode(c.OutputMode) {
+		return fmt.Errorf("unsupported output mode: %s", c.OutputMode)
+	}
+
+	// Azure validation: every model must resolve to a deployment
+	if c.Provider == ProviderAzure {
+		if err := c.AzureConfig.validate(c.Model); err != nil {
+			return err
+		}
+	}
+
+	// Model validation (only OpenAI model names are known ahead of time)
+	if c.Model != "" && c.usesOpenAIModels() && !isValidModel(c.Model) {
+		return fmt.Errorf("unsupported model: %s (register it with RegisterModel)", c.Model)
 	}
 
 	// Timeout validation
@@ -147,11 +295,20 @@ func (c Config) Validate() error {
 		return errors.New("timeout must be positive")
 	}
 
+	// Generation parameter validation
+	if err := validateGeneration(c.Temperature, c.maxTemperature(), c.MaxOutputTokens, c.ReasoningEffort); err != nil {
+		return err
+	}
+
 	// Concurrency validation
 	if c.MaxConcurrent < 0 {
 		return errors.New("MaxConcurrent must be non-negative")
 	}
 
+	if c.BatchTokenBudget < 0 {
+		return errors.New("BatchTokenBudget must be non-negative")
+	}
+
 	// Circuit breaker validation
 	if c.EnableCircuitBreaker && c.CircuitBreakerConfig == nil {
 		return errors.New("circuit breaker enabled but config is nil")
@@ -190,27 +347,72 @@ func (c Config) Validate() error {
 		}
 	}
 
+	// Rubric validation
+	if c.Rubric != nil {
+		if err := c.Rubric.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Scale validation
+	if c.Scale != nil {
+		if err := c.Scale.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Evidence validation
+	if c.Evidence != nil {
+		if err := c.Evidence.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Fallback validation
+	for i, fallback := range c.Fallbacks {
+		if len(fallback.Fallbacks) > 0 {
+			return fmt.Errorf("fallback %d: nested fallbacks are not supported", i)
+		}
+		if fallback.Scale != nil && fallback.resolveScale() != c.resolveScale() {
+			return fmt.Errorf("fallback %d: score scale %s does not match %s", i, fallback.resolveScale(), c.resolveScale())
+		}
+		if err := fallback.Validate(); err != nil {
+			return fmt.Errorf("fallback %d: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
-// isValidModel checks if the model is supported
-func isValidModel(model string) bool {
-	validModels := []string{
-		openai.GPT4,
-		openai.GPT4o,
-		openai.GPT4oMini,
-		openai.GPT4Turbo,
-		openai.GPT432K,
-		openai.GPT3Dot5Turbo,
-		openai.GPT3Dot5Turbo16K,
-	}
-
-	for _, valid := range validModels {
-		if model == valid {
-			return true
+// providerOrDefault returns the configured provider, defaulting to OpenAI
+func (c Config) providerOrDefault() Provider {
+	if c.Provider == "" {
+		return ProviderOpenAI
+	}
+	return c.Provider
+}
+
+// usesOpenAIModels reports whether Config.Model names an OpenAI model. Azure
+// models that map to an explicit deployment are trusted as-is.
+func (c Config) usesOpenAIModels() bool {
+	switch c.providerOrDefault() {
+	case ProviderOpenAI:
+		return true
+	case ProviderAzure:
+		if c.AzureConfig != nil {
+			_, mapped := c.AzureConfig.Deployments[c.Model]
+			return !mapped
 		}
+		return true
+	default:
+		return false
 	}
-	return false
+}
+
+// isValidModel checks if the model is in the capability catalog
+func isValidModel(model string) bool {
+	_, ok := LookupModel(model)
+	return ok
 }
 
 // isValidRetryStrategy checks if the retry strategy is valid
@@ -0,0 +1,311 @@
+package scorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+)
+
+// Aggregation selects how an ensemble combines its samples
+type Aggregation string
+
+const (
+	// AggregateMedian takes the median sample; robust to a single outlier
+	AggregateMedian Aggregation = "median"
+	// AggregateMean takes the mean of the samples
+	AggregateMean Aggregation = "mean"
+	// AggregateMajority takes the most common sample, breaking ties towards the median
+	AggregateMajority Aggregation = "majority"
+)
+
+// DefaultEnsembleSamples is the number of samples taken when neither Samples nor Models is set
+const DefaultEnsembleSamples = 3
+
+// DefaultEnsembleTemperature is the sampling temperature for self-consistency runs
+const DefaultEnsembleTemperature = 0.7
+
+// EnsembleConfig controls how an ensemble scores each item several times
+type EnsembleConfig struct {
+	// Models scores every item once with each model. When empty, the base
+	// scorer's model is sampled Samples times at Temperature instead.
+	Models      []string
+	Samples     int     // Self-consistency samples (default: DefaultEnsembleSamples)
+	Temperature float32 // Sampling temperature for self-consistency (default: DefaultEnsembleTemperature)
+
+	Aggregation Aggregation // How samples are combined (default: AggregateMedian)
+
+	// ReviewSpread flags items whose samples differ by at least this much, in
+	// score scale units, with NeedsReview (0 disables flagging)
+	ReviewSpread float64
+}
+
+// ensembleScorer scores items several times through a base scorer and
+// combines the samples
+type ensembleScorer struct {
+	base    Scorer
+	config  EnsembleConfig
+	metrics *MetricsRecorder
+}
+
+// NewEnsembleScorer creates a scorer that scores every item several times and
+// combines the samples. With Models set it runs the base scorer once per
+// model; otherwise it samples the base scorer's model repeatedly at a non-zero
+// temperature (self-consistency). Runs happen concurrently, and each item
+// combines the runs that scored it, so a failed run or batch only loses
+// samples. Items no run scored are returned Missing, with the error joining
+// their batch errors; the request fails only when every run fails outright.
+//
+// Each result carries the combined score, every sample, their variance and
+// spread, and NeedsReview when the spread reaches ReviewSpread. The reason,
+// backend and rubric dimensions come from the sample closest to the combined
+// score.
+func NewEnsembleScorer(base Scorer, cfg EnsembleConfig) (Scorer, error) {
+	if base == nil {
+		return nil, errors.New("ensemble requires a base scorer")
+	}
+
+	if cfg.Samples == 0 {
+		cfg.Samples = DefaultEnsembleSamples
+	}
+	if cfg.Temperature == 0 {
+		cfg.Temperature = DefaultEnsembleTemperature
+	}
+	if cfg.Aggregation == "" {
+		cfg.Aggregation = AggregateMedian
+	}
+
+	if len(cfg.Models) == 0 && cfg.Samples < 2 {
+		return nil, errors.New("ensemble Samples must be at least 2")
+	}
+	for i, model := range cfg.Models {
+		if model == "" {
+			return nil, fmt.Errorf("ensemble model %d is empty", i)
+		}
+	}
+	switch cfg.Aggregation {
+	case AggregateMedian, AggregateMean, AggregateMajority:
+	default:
+		return nil, fmt.Errorf("unsupported ensemble aggregation: %s", cfg.Aggregation)
+	}
+	if cfg.ReviewSpread < 0 {
+		return nil, errors.New("ensemble ReviewSpread must be non-negative")
+	}
+	if err := validateGeneration(&cfg.Temperature, 2, 0, ""); err != nil {
+		return nil, err
+	}
+
+	return &ensembleScorer{
+		base:    base,
+		config:  cfg,
+		metrics: NewMetricsRecorder(true),
+	}, nil
+}
+
+// ScoreTexts implements Scorer interface with ensemble scoring
+func (s *ensembleScorer) ScoreTexts(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ScoredItem, error) {
+	return s.ScoreTextsWithOptions(ctx, items, opts...)
+}
+
+// ScoreTextsWithOptions implements Scorer interface with ensemble scoring
+func (s *ensembleScorer) ScoreTextsWithOptions(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ScoredItem, error) {
+	runs := s.runOptions(opts)
+
+	slog.Info("Scoring with ensemble",
+		"items", len(items),
+		"runs", len(runs),
+		"aggregation", s.config.Aggregation)
+
+	runResults, runErrs := runAll(ctx, len(runs), len(runs), func(ctx context.Context, i int) ([]ScoredItem, error) {
+		results, err := s.base.ScoreTextsWithOptions(ctx, items, runs[i]...)
+		if results != nil && len(results) != len(items) {
+			return nil, fmt.Errorf("ensemble run returned %d results for %d items", len(results), len(items))
+		}
+		return results, err
+	})
+
+	// Runs that returned results are combined even when some of their batches failed
+	var samples [][]ScoredItem
+	var failed []error
+	for run, err := range runErrs {
+		if err != nil {
+			slog.Warn("Ensemble run failed, combining the other runs",
+				"run", run,
+				"partial", runResults[run] != nil,
+				"error", err)
+			failed = append(failed, fmt.Errorf("ensemble run %d: %w", run, err))
+		}
+		if runResults[run] != nil {
+			samples = append(samples, runResults[run])
+		}
+	}
+	if len(samples) == 0 {
+		return nil, errors.Join(failed...)
+	}
+
+	results := make([]ScoredItem, len(items))
+	for i := range items {
+		itemSamples := make([]ScoredItem, len(samples))
+		for run := range samples {
+			itemSamples[run] = samples[run][i]
+		}
+		results[i] = s.combine(itemSamples)
+
+		if results[i].NeedsReview {
+			slog.Warn("Ensemble samples disagree, flagging item for review",
+				"item_id", results[i].Item.ID,
+				"samples", results[i].Samples,
+				"spread", results[i].Spread)
+			s.metrics.RecordEnsembleReview()
+		}
+	}
+
+	return results, batchErrors(results)
+}
+
+// GetHealth returns the base scorer's health
+func (s *ensembleScorer) GetHealth(ctx context.Context) HealthStatus {
+	health := s.base.GetHealth(ctx)
+	if health.Details == nil {
+		health.Details = make(map[string]interface{})
+	}
+	health.Details["ensemble_runs"] = len(s.runOptions(nil))
+	return health
+}
+
+// runOptions returns the scoring options for each ensemble run: the caller's
+// options plus one model per run, or the sampling temperature repeated
+func (s *ensembleScorer) runOptions(opts []ScoringOption) [][]ScoringOption {
+	var runs [][]ScoringOption
+	if len(s.config.Models) > 0 {
+		for _, model := range s.config.Models {
+			runs = append(runs, append(append([]ScoringOption{}, opts...), WithModel(model)))
+		}
+		return runs
+	}
+
+	for i := 0; i < s.config.Samples; i++ {
+		runs = append(runs, append(append([]ScoringOption{}, opts...), WithTemperature(s.config.Temperature)))
+	}
+	return runs
+}
+
+// combine aggregates one item's samples into a single result. Samples that
+// never scored the item are left out; if none did, the item stays unscored,
+// reporting a failed batch in preference to a missing response.
+func (s *ensembleScorer) combine(samples []ScoredItem) ScoredItem {
+	scored := samples[:0:0]
+	for _, sample := range samples {
+		if !sample.Missing {
+			scored = append(scored, sample)
+		}
+	}
+	if len(scored) == 0 {
+		for _, sample := range samples {
+			var batchErr *BatchError
+			if errors.As(sample.Err, &batchErr) {
+				return sample
+			}
+		}
+		return samples[0]
+	}
+	samples = scored
+
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		values[i] = sample.scoreValue()
+	}
+
+	var value float64
+	switch s.config.Aggregation {
+	case AggregateMean:
+		value = mean(values)
+	case AggregateMajority:
+		value = majority(values)
+	default:
+		value = median(values)
+	}
+
+	// Take the reason and details from the sample that best represents the result
+	closest := 0
+	for i := range values {
+		if math.Abs(values[i]-value) < math.Abs(values[closest]-value) {
+			closest = i
+		}
+	}
+
+	result := samples[closest]
+	result.Score = int(math.Round(value))
+	result.Value = value
+	result.Samples = values
+	result.Variance = variance(values)
+	result.Spread = spread(values)
+	result.NeedsReview = s.config.ReviewSpread > 0 && result.Spread >= s.config.ReviewSpread
+	return result
+}
+
+// mean returns the arithmetic mean
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// median returns the middle value, averaging the two middle values for even counts
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// majority returns the most common value. Ties go to the value closest to the
+// median, then to the lower value.
+func majority(values []float64) float64 {
+	counts := make(map[float64]int, len(values))
+	for _, v := range values {
+		counts[v]++
+	}
+
+	mid := median(values)
+	best, bestCount := values[0], 0
+	for v, count := range counts {
+		switch {
+		case count > bestCount:
+		case count == bestCount && math.Abs(v-mid) < math.Abs(best-mid):
+		case count == bestCount && math.Abs(v-mid) == math.Abs(best-mid) && v < best:
+		default:
+			continue
+		}
+		best, bestCount = v, count
+	}
+	return best
+}
+
+// variance returns the population variance
+func variance(values []float64) float64 {
+	m := mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return sum / float64(len(values))
+}
+
+// spread returns the difference between the highest and lowest value
+func spread(values []float64) float64 {
+	lo, hi := values[0], values[0]
+	for _, v := range values[1:] {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return hi - lo
+}
@@ -0,0 +1,242 @@
+// Package scorer_test covers ensemble scoring: runs across models and
+// self-consistency samples, aggregation, disagreement reporting, and
+// combining the runs that succeed when others fail.
+package scorer_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Ensemble scoring", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Jazz trio at the Blue Note, Friday 8pm"},
+			{ID: "2", Content: "Anyone know a good plumber?"},
+		}
+	})
+
+	// modelScores answers with scores for item 1 and 2 chosen by the request's model
+	modelScores := func(scores map[string][2]int) *mockScoringClient {
+		return &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				s := scores[req.Model]
+				return fmt.Sprintf(`{"version":"1.0","scores":[
+					{"item_id":"1","score":%d,"reason":"%s on 1"},
+					{"item_id":"2","score":%d,"reason":"%s on 2"}]}`, s[0], req.Model, s[1], req.Model)
+			},
+		}
+	}
+
+	It("should score once per model and take the median", func() {
+		client := modelScores(map[string][2]int{
+			"gpt-4o":      {80, 10},
+			"gpt-4o-mini": {90, 12},
+			"gpt-4.1":     {20, 14},
+		})
+		base, err := scorer.NewScorer(scorer.Config{Client: client})
+		Expect(err).ToNot(HaveOccurred())
+
+		s, err := scorer.NewEnsembleScorer(base, scorer.EnsembleConfig{
+			Models:       []string{"gpt-4o", "gpt-4o-mini", "gpt-4.1"},
+			ReviewSpread: 30,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests).To(HaveLen(3))
+
+		Expect(results[0].Score).To(Equal(80))
+		Expect(results[0].Samples).To(Equal([]float64{80, 90, 20}))
+		Expect(results[0].Spread).To(Equal(70.0))
+		Expect(results[0].Variance).To(BeNumerically("~", 955.56, 0.01))
+		Expect(results[0].NeedsReview).To(BeTrue())
+		Expect(results[0].Reason).To(Equal("gpt-4o on 1"))
+		Expect(results[0].Backend).To(Equal("openai/gpt-4o"))
+
+		Expect(results[1].Score).To(Equal(12))
+		Expect(results[1].Spread).To(Equal(4.0))
+		Expect(results[1].NeedsReview).To(BeFalse())
+		Expect(results[1].Reason).To(Equal("gpt-4o-mini on 2"))
+	})
+
+	DescribeTable("aggregation",
+		func(aggregation scorer.Aggregation, expected float64) {
+			client := modelScores(map[string][2]int{
+				"a": {70, 0}, "b": {70, 0}, "c": {90, 0}, "d": {100, 0},
+			})
+			base, err := scorer.NewScorer(scorer.Config{Client: client})
+			Expect(err).ToNot(HaveOccurred())
+
+			s, err := scorer.NewEnsembleScorer(base, scorer.EnsembleConfig{
+				Models:      []string{"a", "b", "c", "d"},
+				Aggregation: aggregation,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, items)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Value).To(Equal(expected))
+		},
+		Entry("median averages the middle pair", scorer.AggregateMedian, 80.0),
+		Entry("mean", scorer.AggregateMean, 82.5),
+		Entry("majority", scorer.AggregateMajority, 70.0),
+	)
+
+	It("should sample one model at temperature for self-consistency", func() {
+		var mu sync.Mutex
+		next := []int{40, 60, 50}
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				mu.Lock()
+				defer mu.Unlock()
+				score := next[0]
+				next = next[1:]
+				return fmt.Sprintf(`{"version":"1.0","scores":[{"item_id":"1","score":%d,"reason":"sample"}]}`, score)
+			},
+		}
+		base, err := scorer.NewScorer(scorer.Config{Client: client, Model: "gpt-4o"})
+		Expect(err).ToNot(HaveOccurred())
+
+		s, err := scorer.NewEnsembleScorer(base, scorer.EnsembleConfig{Temperature: 0.9})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items[:1])
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(client.requests).To(HaveLen(scorer.DefaultEnsembleSamples))
+		for _, req := range client.requests {
+			Expect(req.Model).To(Equal("gpt-4o"))
+			Expect(req.Temperature).To(BeNumerically("~", 0.9, 0.001))
+		}
+		Expect(results[0].Samples).To(ConsistOf(40.0, 60.0, 50.0))
+		Expect(results[0].Score).To(Equal(50))
+	})
+
+	It("should keep fractional combined scores on the scale", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 100, "2": 0}))
+		base, err := scorer.NewScorer(scorer.Config{Client: fake}.WithScale(scorer.LikertScale))
+		Expect(err).ToNot(HaveOccurred())
+
+		s, err := scorer.NewEnsembleScorer(base, scorer.EnsembleConfig{Samples: 2, Aggregation: scorer.AggregateMean})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Value).To(Equal(5.0))
+		Expect(results[1].Value).To(Equal(1.0))
+		Expect(results[1].Variance).To(Equal(0.0))
+	})
+
+	It("should combine only the runs that scored an item", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				if req.Model == "gpt-4.1" {
+					return `{"version":"1.0","scores":[{"item_id":"1","score":20,"reason":"only 1"}]}`
+				}
+				return `{"version":"1.0","scores":[
+					{"item_id":"1","score":80,"reason":"one"},
+					{"item_id":"2","score":10,"reason":"two"}]}`
+			},
+		}
+		base, err := scorer.NewScorer(scorer.Config{Client: client}.WithMaxReasks(-1))
+		Expect(err).ToNot(HaveOccurred())
+
+		s, err := scorer.NewEnsembleScorer(base, scorer.EnsembleConfig{
+			Models:      []string{"gpt-4o", "gpt-4.1"},
+			Aggregation: scorer.AggregateMean,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Value).To(Equal(50.0))
+		Expect(results[1].Value).To(Equal(10.0))
+		Expect(results[1].Samples).To(Equal([]float64{10}))
+		Expect(results[1].Missing).To(BeFalse())
+	})
+
+	It("should combine the runs that succeed when one fails", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 400, Message: "bad request"})
+		base, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		s, err := scorer.NewEnsembleScorer(base, scorer.EnsembleConfig{})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		for _, result := range results {
+			Expect(result.Missing).To(BeFalse())
+			Expect(result.Samples).To(HaveLen(scorer.DefaultEnsembleSamples - 1))
+			Expect(result.Score).To(Equal(scorertest.DefaultScore))
+		}
+	})
+
+	It("should leave items no run scored missing and report their batches", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{Status: 400, Message: "bad request"})
+		base, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		s, err := scorer.NewEnsembleScorer(base, scorer.EnsembleConfig{})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).To(MatchError(scorer.ErrBatchFailed))
+		Expect(err).To(MatchError(ContainSubstring("bad request")))
+		Expect(results).To(HaveLen(2))
+		for _, result := range results {
+			Expect(result.Missing).To(BeTrue())
+			Expect(result.Err).To(MatchError(scorer.ErrBatchFailed))
+		}
+	})
+
+	It("should fail when every run fails outright", func() {
+		base, err := scorer.NewScorer(scorer.Config{Client: scorertest.NewFakeClient()})
+		Expect(err).ToNot(HaveOccurred())
+
+		s, err := scorer.NewEnsembleScorer(base, scorer.EnsembleConfig{})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, []scorer.TextItem{{Content: "No ID"}})
+		Expect(err).To(MatchError(ContainSubstring("empty ID")))
+		Expect(results).To(BeNil())
+	})
+
+	DescribeTable("config validation",
+		func(cfg scorer.EnsembleConfig, message string) {
+			base, err := scorer.NewScorer(scorer.Config{Client: scorertest.NewFakeClient()})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = scorer.NewEnsembleScorer(base, cfg)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("single sample", scorer.EnsembleConfig{Samples: 1}, "Samples must be at least 2"),
+		Entry("empty model", scorer.EnsembleConfig{Models: []string{"gpt-4o", ""}}, "ensemble model 1 is empty"),
+		Entry("unknown aggregation", scorer.EnsembleConfig{Aggregation: "mode"}, "unsupported ensemble aggregation: mode"),
+		Entry("negative review spread", scorer.EnsembleConfig{ReviewSpread: -1}, "ReviewSpread must be non-negative"),
+		Entry("temperature out of range", scorer.EnsembleConfig{Temperature: 3}, "temperature"),
+	)
+
+	It("should require a base scorer", func() {
+		_, err := scorer.NewEnsembleScorer(nil, scorer.EnsembleConfig{})
+		Expect(err).To(MatchError("ensemble requires a base scorer"))
+	})
+})
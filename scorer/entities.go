@@ -0,0 +1,253 @@
+package scorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// EntityConfig enables entity extraction alongside scores and controls how
+// event dates are resolved
+type EntityConfig struct {
+	// Timezone places event dates that do not state a UTC offset (default: UTC)
+	Timezone *time.Location
+	// Reference is the moment relative dates such as "this Friday" are
+	// resolved against (default: the time of the request)
+	Reference time.Time
+}
+
+// Entities are the venues, locations, event dates and prices mentioned in an item
+type Entities struct {
+	Venues    []string    // Venue names, e.g. "The Blue Note"
+	Locations []string    // Street addresses or areas, e.g. "12 High Street" or "Shoreditch"
+	Dates     []EventDate // Event dates resolved to a timezone
+	Prices    []Price     // Price mentions
+}
+
+// EventDate is an event date, time or range mentioned in an item
+type EventDate struct {
+	Text   string    // Date as written in the item
+	Start  time.Time // Start, in the stated offset or EntityConfig.Timezone
+	End    time.Time // End of a range; zero for a single date or time
+	AllDay bool      // The mention gives a date without a time of day
+}
+
+// Price is a price mentioned in an item
+type Price struct {
+	Text     string  // Price as written in the item
+	Amount   float64 // Amount in major units (e.g. 12.50); 0 when free
+	Currency string  // ISO 4217 code, empty when the item does not make it clear
+	Free     bool    // The item says entry is free
+}
+
+// ISO8601 formats the date as ISO-8601: a calendar date for all-day dates,
+// otherwise a date-time with offset, with ranges written as "start/end"
+func (d EventDate) ISO8601() string {
+	layout := time.RFC3339
+	if d.AllDay {
+		layout = time.DateOnly
+	}
+
+	s := d.Start.Format(layout)
+	if !d.End.IsZero() {
+		s += "/" + d.End.Format(layout)
+	}
+	return s
+}
+
+// WithEntities extracts entities with the scores for this request
+func WithEntities(cfg EntityConfig) ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.entities = &cfg
+	}
+}
+
+// Internal response types for entity parsing. Entities sit beside each score
+// and are read in a second pass so every scoring mode can carry them.
+type entityResponse struct {
+	Scores []entityScoreItem `json:"scores"`
+}
+
+type entityScoreItem struct {
+	ItemID   string      `json:"item_id"`
+	Entities rawEntities `json:"entities"`
+}
+
+type rawEntities struct {
+	Venues    []string       `json:"venues" description:"Names of venues such as bars, clubs, galleries or parks"`
+	Locations []string       `json:"locations" description:"Street addresses, neighbourhoods or areas"`
+	Dates     []rawEventDate `json:"dates" description:"Dates or times of the events described"`
+	Prices    []rawPrice     `json:"prices" description:"Ticket, entry or item prices"`
+}
+
+type rawEventDate struct {
+	Text  string `json:"text" description:"The date as written"`
+	Start string `json:"start" description:"ISO-8601 date (2006-01-02) or local date-time (2006-01-02T15:04), with a UTC offset only when the text states a timezone"`
+	End   string `json:"end" description:"End of a range in the same format, or empty"`
+}
+
+type rawPrice struct {
+	Text     string  `json:"text" description:"The price as written"`
+	Amount   float64 `json:"amount" description:"Amount in major units, 0 when free"`
+	Currency string  `json:"currency" description:"ISO 4217 currency code, or empty if unclear"`
+	Free     bool    `json:"free" description:"Whether entry is free"`
+}
+
+// Date layouts accepted for event dates, with and without a UTC offset
+var (
+	offsetDateLayouts = []string{time.RFC3339, "2006-01-02T15:04Z07:00"}
+	localDateLayouts  = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", time.DateOnly}
+)
+
+// resolveEntities returns the entity settings for a request: the per-request
+// option, then the config default, or nil when entities are not requested
+func (c Config) resolveEntities(options *scoringOptions) *EntityConfig {
+	if options != nil && options.entities != nil {
+		return options.entities
+	}
+	return c.Entities
+}
+
+// location returns the timezone for dates without an offset
+func (c EntityConfig) location() *time.Location {
+	if c.Timezone != nil {
+		return c.Timezone
+	}
+	return time.UTC
+}
+
+// reference returns the moment relative dates are resolved against
+func (c EntityConfig) reference() time.Time {
+	if c.Reference.IsZero() {
+		return time.Now().In(c.location())
+	}
+	return c.Reference.In(c.location())
+}
+
+// withEntities adds the entities object to each score in a response schema
+func (c EntityConfig) withEntities(schema *jsonschema.Definition) (*jsonschema.Definition, error) {
+	entities, err := jsonschema.GenerateSchemaForType(rawEntities{})
+	if err != nil {
+		return nil, err
+	}
+	return withScoreProperty(schema, "entities", *entities), nil
+}
+
+// instructions asks the model for entities and explains date resolution
+func (c EntityConfig) instructions() string {
+	ref := c.reference()
+	return fmt.Sprintf("For every item also extract entities: venue names, street addresses or areas, "+
+		"event dates and prices. Only include entities the item mentions. "+
+		"Today is %s, %s in the %s timezone; resolve relative dates such as \"this Friday\" against it. "+
+		"Give dates as ISO-8601 local dates or date-times without an offset unless the item states a timezone.",
+		ref.Weekday(), ref.Format(time.DateOnly), c.location())
+}
+
+// applyEntities reads the entities from the response content and attaches
+// them to the results by item ID, matching entries as the scores were matched.
+// Scored items without entities get an empty set, and missing items get none.
+func (s *scorer) applyEntities(results []ScoredItem, content string, cfg *EntityConfig) error {
+	var response entityResponse
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return fmt.Errorf("failed to parse entities JSON: %w", err)
+	}
+
+	items := make([]TextItem, len(results))
+	for i, result := range results {
+		items[i] = result.Item
+	}
+	entityMap, _, _ := matchIDs(items, response.Scores, func(score entityScoreItem) string {
+		return score.ItemID
+	})
+
+	for i := range results {
+		if results[i].Missing {
+			continue
+		}
+		entities := cfg.resolve(results[i].Item.ID, entityMap[results[i].Item.ID].Entities)
+		results[i].Entities = &entities
+	}
+	return nil
+}
+
+// resolve converts raw entities, parsing dates in the configured timezone.
+// Dates that cannot be parsed are dropped.
+func (c EntityConfig) resolve(itemID string, raw rawEntities) Entities {
+	entities := Entities{
+		Venues:    nonEmpty(raw.Venues),
+		Locations: nonEmpty(raw.Locations),
+	}
+
+	for _, date := range raw.Dates {
+		start, allDay, err := c.parseDate(date.Start)
+		if err != nil {
+			slog.Warn("Dropping unparseable event date",
+				"item_id", itemID,
+				"text", date.Text,
+				"start", date.Start)
+			continue
+		}
+
+		resolved := EventDate{Text: date.Text, Start: start, AllDay: allDay}
+		if strings.TrimSpace(date.End) != "" {
+			end, endAllDay, err := c.parseDate(date.End)
+			if err == nil && !end.Before(start) {
+				resolved.End = end
+				resolved.AllDay = allDay && endAllDay
+			} else {
+				slog.Warn("Ignoring invalid event end date",
+					"item_id", itemID,
+					"text", date.Text,
+					"end", date.End)
+			}
+		}
+		entities.Dates = append(entities.Dates, resolved)
+	}
+
+	for _, price := range raw.Prices {
+		entities.Prices = append(entities.Prices, Price{
+			Text:     price.Text,
+			Amount:   price.Amount,
+			Currency: strings.ToUpper(strings.TrimSpace(price.Currency)),
+			Free:     price.Free,
+		})
+	}
+
+	return entities
+}
+
+// parseDate parses an ISO-8601 date or date-time, placing times without an
+// offset in the configured timezone. allDay is true for bare dates.
+func (c EntityConfig) parseDate(value string) (t time.Time, allDay bool, err error) {
+	value = strings.TrimSpace(value)
+	if len(value) > len(time.DateOnly) && value[len(time.DateOnly)] == ' ' {
+		value = value[:len(time.DateOnly)] + "T" + value[len(time.DateOnly)+1:]
+	}
+
+	for _, layout := range offsetDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, false, nil
+		}
+	}
+	for _, layout := range localDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, c.location()); err == nil {
+			return t, layout == time.DateOnly, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("unrecognised date: %q", value)
+}
+
+// nonEmpty drops blank strings, trimming the rest
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,190 @@
+// Package scorer_test covers entity extraction alongside scores: the extended
+// response schema, date resolution against a timezone, price and venue
+// clean-up, and matching entities to items like their scores.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Entity extraction", func() {
+	var (
+		ctx      context.Context
+		items    []scorer.TextItem
+		london   *time.Location
+		entities scorer.EntityConfig
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Jazz trio at the Blue Note, Friday 8pm, £10 on the door"},
+			{ID: "2", Content: "Anyone know a good plumber?"},
+		}
+		london = time.FixedZone("BST", 60*60)
+		entities = scorer.EntityConfig{
+			Timezone:  london,
+			Reference: time.Date(2026, 10, 14, 9, 0, 0, 0, london),
+		}
+	})
+
+	It("should add entities to the schema and describe the reference date", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithEntities(entities))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := fake.Requests()[0]
+		schema, err := json.Marshal(req.ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).To(ContainSubstring(`"required":["item_id","score","reason","entities"]`))
+		Expect(string(schema)).To(ContainSubstring(`"required":["venues","locations","dates","prices"]`))
+
+		Expect(req.Messages[1].Content).To(ContainSubstring("Today is Wednesday, 2026-10-14 in the BST timezone"))
+	})
+
+	It("should resolve dates in the configured timezone", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithEntities(map[string]any{
+			"1": map[string]any{
+				"venues":    []string{"The Blue Note", " "},
+				"locations": []string{"Soho"},
+				"dates": []map[string]string{
+					{"text": "Friday 8pm", "start": "2026-10-16T20:00", "end": ""},
+					{"text": "all weekend", "start": "2026-10-17", "end": "2026-10-18"},
+					{"text": "9pm New York time", "start": "2026-10-16T21:00:00-04:00", "end": ""},
+					{"text": "soon", "start": "next week", "end": ""},
+					{"text": "Friday", "start": "2026-10-16 19:30", "end": "2026-10-15"},
+				},
+				"prices": []map[string]any{
+					{"text": "£10 on the door", "amount": 10, "currency": "gbp", "free": false},
+				},
+			},
+		}))
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items, scorer.WithEntities(entities))
+		Expect(err).ToNot(HaveOccurred())
+
+		found := results[0].Entities
+		Expect(found).ToNot(BeNil())
+		Expect(found.Venues).To(Equal([]string{"The Blue Note"}))
+		Expect(found.Locations).To(Equal([]string{"Soho"}))
+		Expect(found.Prices).To(Equal([]scorer.Price{{Text: "£10 on the door", Amount: 10, Currency: "GBP"}}))
+
+		Expect(found.Dates).To(HaveLen(4))
+		Expect(found.Dates[0].Start).To(BeTemporally("==", time.Date(2026, 10, 16, 20, 0, 0, 0, london)))
+		Expect(found.Dates[0].ISO8601()).To(Equal("2026-10-16T20:00:00+01:00"))
+
+		Expect(found.Dates[1].AllDay).To(BeTrue())
+		Expect(found.Dates[1].ISO8601()).To(Equal("2026-10-17/2026-10-18"))
+
+		Expect(found.Dates[2].ISO8601()).To(Equal("2026-10-16T21:00:00-04:00"))
+
+		Expect(found.Dates[3].Start).To(BeTemporally("==", time.Date(2026, 10, 16, 19, 30, 0, 0, london)))
+		Expect(found.Dates[3].End.IsZero()).To(BeTrue())
+
+		Expect(results[1].Entities).ToNot(BeNil())
+		Expect(results[1].Entities.Venues).To(BeEmpty())
+		Expect(results[1].Entities.Dates).To(BeEmpty())
+	})
+
+	It("should leave entities nil when they are not requested", func() {
+		s, err := scorer.NewScorer(scorer.Config{Client: scorertest.NewFakeClient()})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Entities).To(BeNil())
+	})
+
+	It("should carry entities with rubric scores", func() {
+		rubric := scorer.Rubric{Dimensions: []scorer.Dimension{{Name: "relevance"}}}
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[{"item_id":"1",
+					"dimensions":{"relevance":{"score":80,"reason":"gig"}},"reason":"gig",
+					"entities":{"venues":["The Blue Note"],"locations":[],"dates":[],"prices":[]}}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client, OutputMode: scorer.OutputModeJSONObject}.WithEntities(entities))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items[:1], scorer.WithRubric(rubric))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(80))
+		Expect(results[0].Entities.Venues).To(Equal([]string{"The Blue Note"}))
+	})
+
+	It("should take entities from the same entry as the score", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				if containsID(req, "2") {
+					return `{"version":"1.0","scores":[
+						{"item_id":"1","score":80,"reason":"gig",
+							"entities":{"venues":["The Blue Note"],"locations":[],"dates":[],"prices":[]}},
+						{"item_id":"1","score":20,"reason":"second answer",
+							"entities":{"venues":["Somewhere else"],"locations":[],"dates":[],"prices":[]}},
+						{"item_id":"2","score":5,"reason":"plumbing",
+							"entities":{"venues":[],"locations":[],"dates":[],"prices":[]}}]}`
+				}
+				return `{"version":"1.0","scores":[{"item_id":"1","score":85,"reason":"re-asked",
+					"entities":{"venues":["Blue Note"],"locations":[],"dates":[],"prices":[]}}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client}.WithEntities(entities))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests).To(HaveLen(2))
+		Expect(results[0].Score).To(Equal(85))
+		Expect(results[0].Entities.Venues).To(Equal([]string{"Blue Note"}))
+		Expect(results[1].Entities.Venues).To(BeEmpty())
+	})
+
+	It("should not attach entities to missing items", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[
+					{"item_id":"1","score":80,"reason":"gig",
+						"entities":{"venues":["The Blue Note"],"locations":[],"dates":[],"prices":[]}},
+					{"item_id":"1","score":20,"reason":"second answer",
+						"entities":{"venues":["Somewhere else"],"locations":[],"dates":[],"prices":[]}}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client}.WithEntities(entities).WithMaxReasks(-1))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		for _, result := range results {
+			Expect(result.Missing).To(BeTrue())
+			Expect(result.Entities).To(BeNil())
+		}
+	})
+
+	It("should reject responses without entities when the schema is checked locally", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[{"item_id":"1","score":80,"reason":"gig"}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client, OutputMode: scorer.OutputModeJSONObject}.WithEntities(entities))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items[:1])
+		Expect(err).To(MatchError(ContainSubstring("failed to validate response JSON")))
+	})
+})
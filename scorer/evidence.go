@@ -0,0 +1,226 @@
+package scorer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// DefaultEvidenceQuotes is the number of quotes requested per item when MaxQuotes is not set
+const DefaultEvidenceQuotes = 3
+
+// EvidenceConfig asks for quotes from each item that support its score
+type EvidenceConfig struct {
+	MaxQuotes      int  // Quotes requested per item (default: DefaultEvidenceQuotes)
+	DropUnverified bool // Drop quotes not found in the item instead of returning them unverified
+}
+
+// Evidence is a quote from an item supporting its score. Offsets count
+// characters (runes), not bytes, so []rune(Content)[Start:End] is the quote
+// as it appears in the item.
+type Evidence struct {
+	Quote    string // Quote as returned by the model
+	Start    int    // Offset of the quote's first character in Item.Content; -1 when unverified
+	End      int    // Offset just past the quote's last character; -1 when unverified
+	Verified bool   // The quote was found in Item.Content
+}
+
+// WithEvidence asks for supporting quotes with the scores for this request
+func WithEvidence(cfg EvidenceConfig) ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.evidence = &cfg
+	}
+}
+
+// Internal response types for evidence parsing, read in a second pass like entities
+type evidenceResponse struct {
+	Scores []evidenceScoreItem `json:"scores"`
+}
+
+type evidenceScoreItem struct {
+	ItemID   string   `json:"item_id"`
+	Evidence []string `json:"evidence"`
+}
+
+// Validate checks that the evidence settings are usable
+func (c EvidenceConfig) Validate() error {
+	if c.MaxQuotes < 0 {
+		return errors.New("evidence MaxQuotes must be non-negative")
+	}
+	return nil
+}
+
+// resolveEvidence returns the evidence settings for a request: the per-request
+// option, then the config default, or nil when evidence is not requested
+func (c Config) resolveEvidence(options *scoringOptions) *EvidenceConfig {
+	if options != nil && options.evidence != nil {
+		return options.evidence
+	}
+	return c.Evidence
+}
+
+// maxQuotes returns the quote limit per item
+func (c EvidenceConfig) maxQuotes() int {
+	if c.MaxQuotes > 0 {
+		return c.MaxQuotes
+	}
+	return DefaultEvidenceQuotes
+}
+
+// withEvidence adds the evidence quotes to each score in a response schema
+func (c EvidenceConfig) withEvidence(schema *jsonschema.Definition) *jsonschema.Definition {
+	return withScoreProperty(schema, "evidence", jsonschema.Definition{
+		Type:        jsonschema.Array,
+		Description: fmt.Sprintf("Up to %d exact quotes from the item supporting the score", c.maxQuotes()),
+		Items:       &jsonschema.Definition{Type: jsonschema.String},
+	})
+}
+
+// instructions asks the model for verbatim quotes
+func (c EvidenceConfig) instructions() string {
+	return fmt.Sprintf("For every item also give up to %d short quotes from the item text that support the score. "+
+		"Copy each quote character for character from a single passage; do not paraphrase, shorten with ellipses or join passages.",
+		c.maxQuotes())
+}
+
+// applyEvidence reads the quotes from the response content, locates each in
+// its item's content and attaches them to the results by item ID, matching
+// entries as the scores were matched. Missing items get no quotes, and quotes
+// that cannot be found are flagged or dropped depending on the config.
+func (s *scorer) applyEvidence(results []ScoredItem, content string, cfg *EvidenceConfig) error {
+	var response evidenceResponse
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return fmt.Errorf("failed to parse evidence JSON: %w", err)
+	}
+
+	items := make([]TextItem, len(results))
+	for i, result := range results {
+		items[i] = result.Item
+	}
+	quoteMap, _, _ := matchIDs(items, response.Scores, func(score evidenceScoreItem) string {
+		return score.ItemID
+	})
+
+	for i := range results {
+		if results[i].Missing {
+			continue
+		}
+		quotes := quoteMap[results[i].Item.ID].Evidence
+		if len(quotes) > cfg.maxQuotes() {
+			quotes = quotes[:cfg.maxQuotes()]
+		}
+
+		for _, quote := range quotes {
+			evidence := Evidence{Quote: quote, Start: -1, End: -1}
+			if start, end, ok := locateQuote(results[i].Item.Content, quote); ok {
+				evidence.Start, evidence.End, evidence.Verified = start, end, true
+			}
+			s.metrics.RecordEvidenceQuote(evidence.Verified)
+
+			if !evidence.Verified {
+				slog.Warn("Evidence quote not found in item content",
+					"item_id", results[i].Item.ID,
+					"quote", quote,
+					"dropped", cfg.DropUnverified)
+				if cfg.DropUnverified {
+					continue
+				}
+			}
+			results[i].Evidence = append(results[i].Evidence, evidence)
+		}
+	}
+	return nil
+}
+
+// locateQuote finds a quote in content and returns its rune offsets. Quotes
+// wrapped in quotation marks are also tried without them.
+func locateQuote(content, quote string) (start, end int, ok bool) {
+	quote = strings.TrimSpace(quote)
+	if start, end, ok := locateSpan(content, quote); ok {
+		return start, end, true
+	}
+
+	runes := []rune(quote)
+	if len(runes) > 2 && isQuoteMark(runes[0]) && isQuoteMark(runes[len(runes)-1]) {
+		return locateSpan(content, strings.TrimSpace(string(runes[1:len(runes)-1])))
+	}
+	return 0, 0, false
+}
+
+// locateSpan tries an exact match first, then a match ignoring case, runs of
+// whitespace and typographic quote and dash variants, which models often normalise
+func locateSpan(content, quote string) (start, end int, ok bool) {
+	if quote == "" {
+		return 0, 0, false
+	}
+
+	if i := strings.Index(content, quote); i >= 0 {
+		start = utf8.RuneCountInString(content[:i])
+		return start, start + utf8.RuneCountInString(quote), true
+	}
+
+	normalContent, offsets := normalizeText(content)
+	normalQuote, _ := normalizeText(quote)
+	i := strings.Index(normalContent, normalQuote)
+	if normalQuote == "" || i < 0 {
+		return 0, 0, false
+	}
+
+	first := utf8.RuneCountInString(normalContent[:i])
+	last := first + utf8.RuneCountInString(normalQuote) - 1
+	return offsets[first], offsets[last] + 1, true
+}
+
+// normalizeText lowercases text, maps quote and dash variants to ASCII and
+// collapses whitespace runs to one space. offsets maps each rune of the result
+// to the rune offset it came from in text.
+func normalizeText(text string) (string, []int) {
+	var sb strings.Builder
+	var offsets []int
+	space := false
+	for i, r := range []rune(text) {
+		switch {
+		case unicode.IsSpace(r):
+			if space || len(offsets) == 0 {
+				continue
+			}
+			space = true
+			r = ' '
+		case r == '‘' || r == '’' || r == '′':
+			r = '\''
+		case r == '“' || r == '”' || r == '″':
+			r = '"'
+		case r == '–' || r == '—' || r == '‐' || r == '−':
+			r = '-'
+		default:
+			r = unicode.ToLower(r)
+		}
+		if r != ' ' {
+			space = false
+		}
+		sb.WriteRune(r)
+		offsets = append(offsets, i)
+	}
+
+	normal := sb.String()
+	if space {
+		normal = normal[:len(normal)-1]
+		offsets = offsets[:len(offsets)-1]
+	}
+	return normal, offsets
+}
+
+// isQuoteMark reports whether r is a quotation mark models wrap quotes in
+func isQuoteMark(r rune) bool {
+	switch r {
+	case '"', '\'', '“', '”', '‘', '’', '«', '»':
+		return true
+	}
+	return false
+}
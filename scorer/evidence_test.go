@@ -0,0 +1,169 @@
+// Package scorer_test covers evidence quotes: the extended response schema,
+// locating quotes in item content as character offsets, flagging or dropping
+// quotes that are not in the content, and matching quotes to items like their
+// scores.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Evidence quotes", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Café Nero on King St: “live” jazz —  Friday 8pm, £5 entry"},
+			{ID: "2", Content: "Anyone know a good plumber?"},
+		}
+	})
+
+	// span returns the text of the content between rune offsets
+	span := func(content string, evidence scorer.Evidence) string {
+		return string([]rune(content)[evidence.Start:evidence.End])
+	}
+
+	It("should add evidence to the schema and ask for verbatim quotes", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithEvidence(scorer.EvidenceConfig{MaxQuotes: 2}))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := fake.Requests()[0]
+		schema, err := json.Marshal(req.ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).To(ContainSubstring(`"required":["item_id","score","reason","evidence"]`))
+		Expect(req.Messages[1].Content).To(ContainSubstring("up to 2 short quotes"))
+
+		Expect(results[1].Evidence).To(Equal([]scorer.Evidence{
+			{Quote: items[1].Content, Start: 0, End: 27, Verified: true},
+		}))
+	})
+
+	It("should return character offsets for exact and normalised quotes", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithEvidence(map[string][]string{
+			"1": {
+				"£5 entry",
+				`"live" jazz - friday 8pm`,
+				"“Café Nero”",
+			},
+		}))
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items, scorer.WithEvidence(scorer.EvidenceConfig{}))
+		Expect(err).ToNot(HaveOccurred())
+
+		evidence := results[0].Evidence
+		Expect(evidence).To(HaveLen(3))
+		for _, e := range evidence {
+			Expect(e.Verified).To(BeTrue(), e.Quote)
+		}
+		Expect(span(items[0].Content, evidence[0])).To(Equal("£5 entry"))
+		Expect(evidence[0].Start).To(Equal(49))
+		Expect(span(items[0].Content, evidence[1])).To(Equal("“live” jazz —  Friday 8pm"))
+		Expect(span(items[0].Content, evidence[2])).To(Equal("Café Nero"))
+	})
+
+	It("should flag quotes that are not in the content", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithEvidence(map[string][]string{
+			"1": {"Free entry all night", "£5 entry"},
+		}))
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items, scorer.WithEvidence(scorer.EvidenceConfig{}))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(results[0].Evidence).To(HaveLen(2))
+		Expect(results[0].Evidence[0]).To(Equal(scorer.Evidence{Quote: "Free entry all night", Start: -1, End: -1}))
+		Expect(results[0].Evidence[1].Verified).To(BeTrue())
+	})
+
+	It("should drop unverified quotes and keep at most MaxQuotes", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithEvidence(map[string][]string{
+			"1": {"Free entry all night", "£5 entry", "King St", "Friday 8pm"},
+		}))
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items, scorer.WithEvidence(scorer.EvidenceConfig{
+			MaxQuotes:      3,
+			DropUnverified: true,
+		}))
+		Expect(err).ToNot(HaveOccurred())
+
+		quotes := make([]string, len(results[0].Evidence))
+		for i, e := range results[0].Evidence {
+			quotes[i] = e.Quote
+		}
+		Expect(quotes).To(Equal([]string{"£5 entry", "King St"}))
+	})
+
+	It("should take quotes from the same entry as the score", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				if containsID(req, "2") {
+					return `{"version":"1.0","scores":[
+						{"item_id":"1","score":80,"reason":"gig","evidence":["live"]},
+						{"item_id":"1","score":20,"reason":"second answer","evidence":["King St"]},
+						{"item_id":"2","score":5,"reason":"plumbing","evidence":["plumber"]}]}`
+				}
+				return `{"version":"1.0","scores":[{"item_id":"1","score":85,"reason":"re-asked","evidence":["Friday 8pm"]}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client}.WithEvidence(scorer.EvidenceConfig{}))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests).To(HaveLen(2))
+		Expect(results[0].Evidence).To(HaveLen(1))
+		Expect(results[0].Evidence[0].Quote).To(Equal("Friday 8pm"))
+		Expect(results[1].Evidence[0].Quote).To(Equal("plumber"))
+	})
+
+	It("should not attach quotes to missing items", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[
+					{"item_id":"1","score":80,"reason":"gig","evidence":["live"]},
+					{"item_id":"1","score":20,"reason":"second answer","evidence":["King St"]}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client}.WithEvidence(scorer.EvidenceConfig{}).WithMaxReasks(-1))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		for _, result := range results {
+			Expect(result.Missing).To(BeTrue())
+			Expect(result.Evidence).To(BeEmpty())
+		}
+	})
+
+	It("should reject a negative quote limit", func() {
+		s, err := scorer.NewScorer(scorer.Config{Client: scorertest.NewFakeClient()})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items, scorer.WithEvidence(scorer.EvidenceConfig{MaxQuotes: -1}))
+		Expect(err).To(MatchError("evidence MaxQuotes must be non-negative"))
+
+		cfg := scorer.Config{Client: scorertest.NewFakeClient()}.WithEvidence(scorer.EvidenceConfig{MaxQuotes: -1})
+		Expect(cfg.Validate()).To(MatchError("evidence MaxQuotes must be non-negative"))
+	})
+})
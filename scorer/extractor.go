@@ -0,0 +1,255 @@
+package scorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+var extractSystemPrompt string
+var extractPrompt string
+var extractPromptError error
+
+func init() {
+	systemBytes, err := promptFS.ReadFile("prompts/extract_system_prompt.txt")
+	if err != nil {
+		extractPromptError = fmt.Errorf("failed to load extract system prompt: %w", err)
+		return
+	}
+	extractSystemPrompt = string(systemBytes)
+
+	promptBytes, err := promptFS.ReadFile("prompts/extract_prompt.txt")
+	if err != nil {
+		extractPromptError = fmt.Errorf("failed to load extract prompt: %w", err)
+		return
+	}
+	extractPrompt = string(promptBytes)
+}
+
+// Extractor pulls structured fields of type T out of text items
+type Extractor[T any] interface {
+	// Extract runs extraction on every item, returning results in input
+	// order. When some batches fail, the results are still returned alongside
+	// the error, with the failed batches' items not Found.
+	Extract(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]Extracted[T], error)
+}
+
+// Extracted is a text item with the fields extracted from it
+type Extracted[T any] struct {
+	Item    TextItem // Original text item
+	Data    T        // Extracted fields; the zero value when Found is false
+	Found   bool     // Whether the response contained an extraction for the item that decoded into T
+	Backend string   // Backend that produced the extraction, as "provider/model"
+	Err     error    // Why Found is false: ErrItemMissing, the error decoding the item's data, or a *BatchError
+}
+
+// Internal response types for extraction JSON parsing. Data is decoded into T
+// per item once the item is matched.
+type extractResponse struct {
+	Version     string           `json:"version"`
+	Extractions []extractionItem `json:"extractions"`
+}
+
+type extractionItem struct {
+	ItemID string          `json:"item_id"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// extractor batches items through a scorer's client, prompts and output mode handling
+type extractor[T any] struct {
+	scorer        *scorer
+	schema        *jsonschema.Definition
+	outputPerItem int // Expected output tokens per item, estimated from the data schema
+}
+
+// NewExtractor creates an extractor for the struct type T. The response
+// schema is generated from T with jsonschema.GenerateSchemaForType, so json
+// tags name the fields and description tags explain them to the model. Strict
+// schema mode requires every field, so avoid omitempty on fields of T.
+//
+// Items are batched and validated as for scoring, batches run concurrently up
+// to Config.MaxConcurrent without a failed batch cancelling the others, and
+// the config's retry and circuit breaker settings apply to every call.
+func NewExtractor[T any](cfg Config) (Extractor[T], error) {
+	if extractPromptError != nil {
+		return nil, extractPromptError
+	}
+
+	var zero T
+	data, err := jsonschema.GenerateSchemaForType(zero)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate extraction schema: %w", err)
+	}
+	if data.Type != jsonschema.Object {
+		return nil, fmt.Errorf("extraction type must be a struct, got %T", zero)
+	}
+
+	s, err := newPerCallScorer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate extraction schema: %w", err)
+	}
+	outputPerItem := extractionOutputRatio * estimateTokens(string(schemaJSON), charsPerToken(cfg.resolveModel(nil)))
+
+	return &extractor[T]{scorer: s, schema: extractSchema(data), outputPerItem: outputPerItem}, nil
+}
+
+// Extract implements Extractor
+func (e *extractor[T]) Extract(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]Extracted[T], error) {
+	if items == nil {
+		return nil, errors.New("items cannot be nil")
+	}
+
+	if len(items) == 0 {
+		return []Extracted[T]{}, nil
+	}
+
+	if err := e.scorer.validateItems(items); err != nil {
+		return nil, err
+	}
+
+	options := &scoringOptions{
+		model:        e.scorer.config.Model,
+		promptText:   extractPrompt,
+		systemPrompt: extractSystemPrompt,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if err := e.scorer.config.validateOptions(options); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	e.scorer.metrics.RecordBatchSize(len(items))
+
+	budget := e.scorer.config.newBatchBudget(options, []string{options.systemPrompt, options.promptText}, e.outputPerItem)
+	batches := budget.split(items)
+
+	outcomes, errs := runAll(ctx, len(batches), e.scorer.config.MaxConcurrent, func(ctx context.Context, i int) ([]Extracted[T], error) {
+		return e.extractBatch(ctx, batches[i], options)
+	})
+	extracted, err := collectBatches(batches, outcomes, errs, func(item TextItem, batchErr *BatchError) Extracted[T] {
+		return Extracted[T]{Item: item, Err: batchErr}
+	})
+	e.scorer.metrics.recordOutcome(e.scorer.config.resolveModel(options), start, err)
+
+	slog.Info("Completed extraction",
+		"total_batches", len(batches),
+		"total_items", len(extracted))
+
+	// Items of failed batches are not Found, and err joins their batch errors
+	return extracted, err
+}
+
+// extractBatch extracts fields for one batch, then re-asks for the items
+// missing from the response up to the configured re-ask limit
+func (e *extractor[T]) extractBatch(ctx context.Context, batch []TextItem, options *scoringOptions) ([]Extracted[T], error) {
+	results, err := e.requestExtractions(ctx, batch, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return reaskMissing(ctx, e.scorer, results, func(result Extracted[T]) bool {
+		return errors.Is(result.Err, ErrItemMissing)
+	}, func(result Extracted[T]) TextItem {
+		return result.Item
+	}, func(ctx context.Context, missing []TextItem) ([]Extracted[T], error) {
+		return e.requestExtractions(ctx, missing, options)
+	})
+}
+
+// requestExtractions sends one extraction request and maps the fields back
+// to the batch's items
+func (e *extractor[T]) requestExtractions(ctx context.Context, batch []TextItem, options *scoringOptions) ([]Extracted[T], error) {
+	prompt, err := e.scorer.formatPrompt(options.promptText, batch, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format prompt: %w", err)
+	}
+
+	slog.Info("Extracting from batch of text items", "batch_size", len(batch))
+
+	resp, err := e.scorer.completeJSON(ctx, prompt, e.schema, options, len(batch))
+	if err != nil {
+		return nil, err
+	}
+
+	var response extractResponse
+	if err := json.Unmarshal([]byte(resp.content), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response JSON: %w", err)
+	}
+	slog.Info("Received extractions from OpenAI", "extractions_count", len(response.Extractions))
+
+	results := e.mapExtractionsToItems(batch, response.Extractions)
+
+	for i := range results {
+		results[i].Backend = resp.backend
+	}
+	return results, nil
+}
+
+// mapExtractionsToItems matches extractions to input items by ID through
+// reconcileIDs and decodes their data. Items without exactly one extraction
+// keep Found false with ErrItemMissing, and an item whose data does not decode
+// keeps Found false with the decode error, without affecting the others.
+func (e *extractor[T]) mapExtractionsToItems(items []TextItem, extractions []extractionItem) []Extracted[T] {
+	extractionMap := reconcileIDs(items, extractions, func(extraction extractionItem) string {
+		return extraction.ItemID
+	}, e.scorer.metrics)
+
+	results := make([]Extracted[T], len(items))
+	for i, item := range items {
+		results[i] = Extracted[T]{Item: item}
+
+		extraction, found := extractionMap[item.ID]
+		if !found {
+			slog.Warn("Extraction not found for item", "item_id", item.ID)
+			results[i].Err = ErrItemMissing
+			continue
+		}
+
+		var data T
+		if err := json.Unmarshal(extraction.Data, &data); err != nil {
+			slog.Warn("Failed to parse extraction for item", "item_id", item.ID, "error", err)
+			results[i].Err = fmt.Errorf("failed to parse extraction for item %s: %w", item.ID, err)
+			continue
+		}
+		results[i].Data = data
+		results[i].Found = true
+	}
+
+	return results
+}
+
+// extractSchema wraps the schema for one item's data in the batch response schema
+func extractSchema(data *jsonschema.Definition) *jsonschema.Definition {
+	return &jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"version": {Type: jsonschema.String},
+			"extractions": {
+				Type: jsonschema.Array,
+				Items: &jsonschema.Definition{
+					Type: jsonschema.Object,
+					Properties: map[string]jsonschema.Definition{
+						"item_id": {Type: jsonschema.String},
+						"data":    *data,
+					},
+					Required:             []string{"item_id", "data"},
+					AdditionalProperties: false,
+				},
+			},
+		},
+		Required:             []string{"version", "extractions"},
+		AdditionalProperties: false,
+	}
+}
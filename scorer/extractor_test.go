@@ -0,0 +1,261 @@
+// Package scorer_test covers generic structured extraction: schemas derived
+// from caller structs, mapping extractions back by ID, re-asking for missing
+// items, per-item decode errors, batching, keeping the batches that succeed,
+// retries and item validation.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+// listing is the caller struct the extraction tests pull out of posts
+type listing struct {
+	Title string   `json:"title" description:"Short title for the listing"`
+	Price float64  `json:"price" description:"Asking price, 0 if not stated"`
+	Tags  []string `json:"tags"`
+}
+
+var _ = Describe("Extractor", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Selling a road bike, barely used, £250"},
+			{ID: "2", Content: "Free sofa, collection only"},
+		}
+	})
+
+	It("should derive the response schema from the caller's struct", func() {
+		fake := scorertest.NewFakeClient()
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = extractor.Extract(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := fake.Requests()[0]
+		Expect(req.ResponseFormat.JSONSchema.Strict).To(BeTrue())
+		schema, err := json.Marshal(req.ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).To(ContainSubstring(`"extractions"`))
+		Expect(string(schema)).To(ContainSubstring(`"description":"Asking price, 0 if not stated"`))
+		Expect(string(schema)).To(ContainSubstring(`"required":["title","price","tags"]`))
+
+		Expect(req.Messages[0].Content).To(ContainSubstring("structured data extractor"))
+		Expect(req.Messages[1].Content).To(ContainSubstring("Text items to extract from"))
+	})
+
+	It("should map extractions back to items by ID", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithExtractions(map[string]any{
+			"1": listing{Title: "Road bike", Price: 250, Tags: []string{"bike"}},
+		}))
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: fake, Model: "gpt-4o-mini"})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := extractor.Extract(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].Item.ID).To(Equal("1"))
+		Expect(results[0].Found).To(BeTrue())
+		Expect(results[0].Data).To(Equal(listing{Title: "Road bike", Price: 250, Tags: []string{"bike"}}))
+		Expect(results[0].Backend).To(Equal("openai/gpt-4o-mini"))
+
+		Expect(results[1].Found).To(BeTrue())
+		Expect(results[1].Data.Title).To(BeEmpty())
+	})
+
+	It("should re-ask for items missing from the response", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{MissingIDs: []string{"2"}})
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := extractor.Extract(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(Equal(2))
+		Expect(results[0].Found).To(BeTrue())
+		Expect(results[1].Found).To(BeTrue())
+		Expect(results[1].Err).ToNot(HaveOccurred())
+	})
+
+	It("should mark items never extracted as not found", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{MissingIDs: []string{"2"}})
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := extractor.Extract(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Found).To(BeTrue())
+		Expect(results[1].Found).To(BeFalse())
+		Expect(results[1].Err).To(MatchError(scorer.ErrItemMissing))
+		Expect(results[1].Data).To(Equal(listing{}))
+	})
+
+	It("should re-ask for duplicated IDs and ignore unknown ones", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				if containsID(req, "2") {
+					return `{"version":"1.0","extractions":[
+						{"item_id":"1","data":{"title":"Road bike","price":250,"tags":[]}},
+						{"item_id":"1","data":{"title":"Other bike","price":0,"tags":[]}},
+						{"item_id":"9","data":{"title":"Not in the batch","price":0,"tags":[]}},
+						{"item_id":"2","data":{"title":"Sofa","price":0,"tags":[]}}]}`
+				}
+				return `{"version":"1.0","extractions":[{"item_id":"1","data":{"title":"Re-asked","price":250,"tags":[]}}]}`
+			},
+		}
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: client})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := extractor.Extract(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests).To(HaveLen(2))
+		Expect(results[0].Data.Title).To(Equal("Re-asked"))
+		Expect(results[1].Data.Title).To(Equal("Sofa"))
+	})
+
+	It("should keep the rest of the batch when one item's data does not decode", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","extractions":[
+					{"item_id":"1","data":{"title":"Road bike","price":"two hundred and fifty","tags":[]}},
+					{"item_id":"2","data":{"title":"Sofa","price":0,"tags":[]}}]}`
+			},
+		}
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: client})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := extractor.Extract(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests).To(HaveLen(1))
+		Expect(results[0].Found).To(BeFalse())
+		Expect(results[0].Err).To(MatchError(ContainSubstring("failed to parse extraction for item 1")))
+		Expect(results[0].Data).To(Equal(listing{}))
+		Expect(results[1].Found).To(BeTrue())
+		Expect(results[1].Data.Title).To(Equal("Sofa"))
+	})
+
+	It("should validate responses locally when the schema is not enforced", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","extractions":[{"item_id":"1","data":{"title":"Road bike"}}]}`
+			},
+		}
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: client, OutputMode: scorer.OutputModeJSONObject})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = extractor.Extract(ctx, items)
+		Expect(err).To(MatchError(ContainSubstring("failed to validate response JSON")))
+	})
+
+	It("should batch large inputs and keep input order", func() {
+		items = nil
+		scripted := make(map[string]any)
+		for i := 0; i < 25; i++ {
+			id := fmt.Sprintf("item-%d", i)
+			items = append(items, scorer.TextItem{ID: id, Content: fmt.Sprintf("Post number %d", i)})
+			scripted[id] = listing{Title: id, Tags: []string{}}
+		}
+		fake := scorertest.NewFakeClient(scorertest.WithExtractions(scripted))
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: fake, MaxConcurrent: 3})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := extractor.Extract(ctx, items, scorer.WithTokenBudget(1000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(BeNumerically(">", 1))
+		Expect(results).To(HaveLen(25))
+		for i, result := range results {
+			Expect(result.Item.ID).To(Equal(items[i].ID))
+			Expect(result.Data.Title).To(Equal(items[i].ID))
+		}
+	})
+
+	It("should keep the extractions of batches that succeed when others fail", func() {
+		items = nil
+		scripted := make(map[string]any)
+		for i := 0; i < 25; i++ {
+			id := fmt.Sprintf("item-%d", i)
+			items = append(items, scorer.TextItem{ID: id, Content: fmt.Sprintf("Post number %d", i)})
+			scripted[id] = listing{Title: id, Tags: []string{}}
+		}
+		fake := scorertest.NewFakeClient(scorertest.WithExtractions(scripted))
+		fake.FailNext(scorertest.Fault{Status: 400, Message: "bad request"})
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := extractor.Extract(ctx, items, scorer.WithTokenBudget(1000))
+		Expect(err).To(MatchError(scorer.ErrBatchFailed))
+		Expect(err).To(MatchError(ContainSubstring("bad request")))
+		Expect(results).To(HaveLen(25))
+
+		// The first batch failed and the rest were extracted
+		Expect(results[0].Found).To(BeFalse())
+		Expect(results[0].Err).To(MatchError(ContainSubstring("(batch 0)")))
+		Expect(results[24].Found).To(BeTrue())
+		for i, result := range results {
+			Expect(result.Item.ID).To(Equal(items[i].ID))
+			if result.Found {
+				Expect(result.Data.Title).To(Equal(items[i].ID))
+			} else {
+				Expect(result.Err).To(MatchError(scorer.ErrBatchFailed))
+			}
+		}
+	})
+
+	It("should retry failed calls when retry is enabled", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 503})
+		cfg := scorer.Config{Client: fake}.WithRetryConfig(&scorer.RetryConfig{
+			MaxAttempts:  2,
+			Strategy:     scorer.RetryStrategyConstant,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     time.Millisecond,
+		})
+		extractor, err := scorer.NewExtractor[listing](cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := extractor.Extract(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+		Expect(fake.Calls()).To(Equal(2))
+	})
+
+	It("should validate items like scoring does", func() {
+		extractor, err := scorer.NewExtractor[listing](scorer.Config{Client: scorertest.NewFakeClient()})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = extractor.Extract(ctx, nil)
+		Expect(err).To(MatchError("items cannot be nil"))
+
+		results, err := extractor.Extract(ctx, []scorer.TextItem{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(BeEmpty())
+
+		_, err = extractor.Extract(ctx, []scorer.TextItem{{ID: "", Content: "text"}})
+		Expect(err).To(MatchError(ContainSubstring("empty ID")))
+	})
+
+	It("should reject types that are not structs", func() {
+		_, err := scorer.NewExtractor[[]string](scorer.Config{Client: scorertest.NewFakeClient()})
+		Expect(err).To(MatchError(ContainSubstring("extraction type must be a struct")))
+
+		_, err = scorer.NewExtractor[map[string]string](scorer.Config{Client: scorertest.NewFakeClient()})
+		Expect(err).To(MatchError(ContainSubstring("failed to generate extraction schema")))
+	})
+})
@@ -0,0 +1,230 @@
+package scorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sony/gobreaker/v2"
+)
+
+// Backend is one entry in a fallback chain
+type Backend struct {
+	Name   string // Label used in logs, metrics and health details
+	Scorer Scorer // Scorer for this backend, usually with its own retry and circuit breaker
+}
+
+// fallbackScorer tries an ordered list of backends, moving to the next one
+// when the current backend is unavailable
+type fallbackScorer struct {
+	backends []Backend
+	metrics  *MetricsRecorder
+}
+
+// NewFallbackScorer creates a scorer that tries each backend in order. It moves
+// to the next backend when the current one has its circuit breaker open,
+// returns a server or authentication error, or exhausts its retries on rate
+// limits; when only some batches failed, each is judged by its own error and
+// only the items of those batches move. Caller cancellation and request
+// errors are returned immediately, since another backend would fail the same
+// way.
+//
+// A per-request WithModel override applies to the first backend only; later
+// backends use their configured model.
+func NewFallbackScorer(backends ...Backend) (Scorer, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("at least one backend is required")
+	}
+	for i, backend := range backends {
+		if backend.Scorer == nil {
+			return nil, fmt.Errorf("backend %d (%s) has no scorer", i, backend.Name)
+		}
+	}
+
+	return &fallbackScorer{
+		backends: backends,
+		metrics:  NewMetricsRecorder(true),
+	}, nil
+}
+
+// ScoreTexts implements Scorer interface with backend fallback
+func (s *fallbackScorer) ScoreTexts(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ScoredItem, error) {
+	return s.ScoreTextsWithOptions(ctx, items, opts...)
+}
+
+// ScoreTextsWithOptions implements Scorer interface with backend fallback
+func (s *fallbackScorer) ScoreTextsWithOptions(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ScoredItem, error) {
+	var errs []error
+	var results []ScoredItem
+	var positions []int
+
+	pending := items
+	for i, backend := range s.backends {
+		backendOpts := opts
+		if i > 0 {
+			backendOpts = append(append([]ScoringOption{}, opts...), withConfiguredModel())
+		}
+
+		scored, err := backend.Scorer.ScoreTextsWithOptions(ctx, pending, backendOpts...)
+		for j := range scored {
+			if scored[j].Backend == "" {
+				scored[j].Backend = backend.Name
+			}
+		}
+		if results == nil {
+			results = scored
+		} else {
+			mergeRescored(results, positions, scored, err)
+		}
+		if results != nil {
+			// Batches kept from earlier backends still count as failed
+			err = batchErrors(results)
+		}
+
+		if err == nil {
+			if i > 0 {
+				slog.Info("Scored with fallback backend",
+					"backend", backend.Name,
+					"position", i,
+					"items", len(pending))
+			}
+			return results, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", backend.Name, err))
+
+		// Each failed batch is judged by its own error when there are results
+		fallback := shouldFallback(ctx, err)
+		if results != nil {
+			pending, positions = failedBatchItems(results, func(err error) bool {
+				return shouldFallback(ctx, err)
+			})
+			fallback = len(pending) > 0
+		}
+		if !fallback {
+			return results, err
+		}
+
+		if i+1 < len(s.backends) {
+			next := s.backends[i+1].Name
+			slog.Warn("Backend unavailable, falling back",
+				"backend", backend.Name,
+				"next", next,
+				"items", len(pending),
+				"error", err)
+			s.metrics.RecordFallback(backend.Name, next)
+		}
+	}
+
+	return results, fmt.Errorf("all %d backends failed: %w", len(s.backends), errors.Join(errs...))
+}
+
+// GetHealth reports healthy while any backend is healthy, and degraded when
+// the primary backend is not
+func (s *fallbackScorer) GetHealth(ctx context.Context) HealthStatus {
+	backends := make(map[string]interface{}, len(s.backends))
+	var healthy []string
+
+	for _, backend := range s.backends {
+		health := backend.Scorer.GetHealth(ctx)
+		backends[backend.Name] = health.Status
+		if health.Healthy {
+			healthy = append(healthy, backend.Name)
+		}
+	}
+
+	status := HealthStatus{
+		Healthy: len(healthy) > 0,
+		Details: map[string]interface{}{
+			"backends":         backends,
+			"healthy_backends": healthy,
+		},
+	}
+
+	switch {
+	case len(healthy) == 0:
+		status.Status = "unhealthy"
+	case healthy[0] != s.backends[0].Name:
+		status.Status = fmt.Sprintf("degraded (serving from %s)", healthy[0])
+	default:
+		status.Status = "healthy"
+	}
+
+	return status
+}
+
+// shouldFallback reports whether an error means the backend is unavailable
+// rather than the request being at fault. Only an open circuit breaker, an
+// unavailable status, a network failure or a response the backend got wrong
+// moves to the next backend; anything else, including caller deadlines and
+// invalid items, rubrics or templates, would fail the same way everywhere.
+func shouldFallback(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return true
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return unavailableStatus(apiErr.HTTPStatusCode)
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return unavailableStatus(reqErr.HTTPStatusCode)
+	}
+
+	// Network failures are specific to this backend
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	// So are responses that are empty, do not parse or do not match the schema
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) ||
+		errors.Is(err, ErrSchemaMismatch) || errors.Is(err, ErrNoChoices)
+}
+
+// unavailableStatus reports whether an HTTP status means the backend cannot
+// serve the request, as opposed to the request being invalid
+func unavailableStatus(status int) bool {
+	switch {
+	case status == 429: // Retries exhausted on rate limits
+		return true
+	case status == 401, status == 403: // Credentials rejected by this vendor
+		return true
+	case status == 404: // Model or deployment missing on this backend
+		return true
+	default:
+		return status >= 500
+	}
+}
+
+// withConfiguredModel clears a per-request model override so a fallback
+// backend uses the model from its own config
+func withConfiguredModel() ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.model = ""
+	}
+}
+
+// backendLabel names a backend as "provider/model"
+func backendLabel(provider Provider, model string) string {
+	return fmt.Sprintf("%s/%s", provider, model)
+}
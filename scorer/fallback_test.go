@@ -0,0 +1,229 @@
+// Package scorer_test verifies backend fallback chains: which failures move
+// traffic to the next backend, which are returned immediately, and that every
+// scored item records the backend that produced it.
+package scorer_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+	"github.com/sony/gobreaker/v2"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+)
+
+var _ = Describe("Fallback chain", func() {
+	var (
+		ctx       context.Context
+		primary   *mockScoringClient
+		secondary *mockScoringClient
+		items     []scorer.TextItem
+		cfg       scorer.Config
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		primary = &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[{"item_id":"1","score":90,"reason":"primary"}]}`
+			},
+		}
+		secondary = &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[{"item_id":"1","score":40,"reason":"secondary"}]}`
+			},
+		}
+		items = []scorer.TextItem{{ID: "1", Content: "Open mic at the Crown"}}
+
+		cfg = scorer.Config{Client: primary, Model: openai.GPT4oMini}.
+			WithFallback(scorer.Config{
+				Provider: scorer.ProviderAnthropic,
+				Client:   secondary,
+				Model:    scorer.DefaultAnthropicModel,
+			})
+	})
+
+	It("should use the primary backend when it is available", func() {
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(90))
+		Expect(results[0].Backend).To(Equal("openai/gpt-4o-mini"))
+		Expect(secondary.requests).To(BeEmpty())
+	})
+
+	DescribeTable("should fall back when the primary is unavailable",
+		func(status int) {
+			primary.err = &openai.APIError{HTTPStatusCode: status, Message: "unavailable"}
+
+			s, err := scorer.NewIntegratedScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, items)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Score).To(Equal(40))
+			Expect(results[0].Backend).To(Equal("anthropic/" + scorer.DefaultAnthropicModel))
+		},
+		Entry("server error", 503),
+		Entry("rate limit after retries", 429),
+		Entry("rejected credentials", 401),
+	)
+
+	It("should fall back while the primary circuit breaker is open", func() {
+		primaryCfg := scorer.Config{Client: primary, Model: openai.GPT4oMini}.WithCircuitBreaker()
+		primaryCfg.CircuitBreakerConfig.ReadyToTrip = func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		}
+		primary.err = &openai.APIError{HTTPStatusCode: 500, Message: "boom"}
+
+		s, err := scorer.NewIntegratedScorer(primaryCfg.WithFallback(cfg.Fallbacks[0]))
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := 0; i < 3; i++ {
+			results, err := s.ScoreTexts(ctx, items)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Backend).To(HavePrefix("anthropic/"))
+		}
+
+		// Only the call that tripped the breaker reached the primary
+		Expect(primary.requests).To(HaveLen(1))
+		Expect(secondary.requests).To(HaveLen(3))
+	})
+
+	It("should not fall back on request errors", func() {
+		primary.err = &openai.APIError{HTTPStatusCode: 400, Message: "bad request"}
+
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).To(HaveOccurred())
+		Expect(secondary.requests).To(BeEmpty())
+	})
+
+	It("should not fall back when the caller cancels", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		primary.err = context.Canceled
+
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(cancelled, items)
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(secondary.requests).To(BeEmpty())
+	})
+
+	It("should not fall back when the primary times out", func() {
+		primary.err = context.DeadlineExceeded
+
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(secondary.requests).To(BeEmpty())
+	})
+
+	It("should not fall back on invalid items", func() {
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, []scorer.TextItem{{Content: "No ID"}})
+		Expect(err).To(HaveOccurred())
+		Expect(primary.requests).To(BeEmpty())
+		Expect(secondary.requests).To(BeEmpty())
+	})
+
+	It("should fall back on responses that are not JSON", func() {
+		primary.respond = func(req openai.ChatCompletionRequest) string { return "not json" }
+
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(40))
+	})
+
+	It("should fall back per call in classification", func() {
+		primary.err = &openai.APIError{HTTPStatusCode: 503, Message: "unavailable"}
+		secondary.respond = func(req openai.ChatCompletionRequest) string {
+			return `{"version":"1.0","classifications":[
+				{"item_id":"1","labels":[{"label":"event","reason":"open mic","confidence":0.8}]}]}`
+		}
+
+		classifier, err := scorer.NewClassifier(cfg, scorer.LabelSet{Labels: []scorer.Label{
+			{Name: "event"},
+			{Name: "other"},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Labels[0].Label).To(Equal("event"))
+		Expect(results[0].Backend).To(Equal("anthropic/" + scorer.DefaultAnthropicModel))
+		Expect(primary.requests).To(HaveLen(1))
+		Expect(secondary.requests).To(HaveLen(1))
+	})
+
+	It("should not fall back per call on request errors", func() {
+		primary.err = &openai.APIError{HTTPStatusCode: 400, Message: "bad request"}
+
+		classifier, err := scorer.NewClassifier(cfg, scorer.LabelSet{Labels: []scorer.Label{
+			{Name: "event"},
+			{Name: "other"},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = classifier.Classify(ctx, items)
+		Expect(err).To(HaveOccurred())
+		Expect(secondary.requests).To(BeEmpty())
+	})
+
+	It("should apply a per-request model override to the primary only", func() {
+		primary.err = &openai.APIError{HTTPStatusCode: 502, Message: "bad gateway"}
+
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items, scorer.WithModel(openai.GPT4o))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(primary.requests[0].Model).To(Equal(openai.GPT4o))
+		Expect(secondary.requests[0].Model).To(Equal(scorer.DefaultAnthropicModel))
+	})
+
+	It("should report every failure when all backends are down", func() {
+		primary.err = &openai.APIError{HTTPStatusCode: 503, Message: "primary down"}
+		secondary.err = &openai.APIError{HTTPStatusCode: 529, Message: "overloaded"}
+
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).To(MatchError(ContainSubstring("all 2 backends failed")))
+		Expect(err).To(MatchError(ContainSubstring("primary down")))
+		Expect(err).To(MatchError(ContainSubstring("overloaded")))
+	})
+
+	Describe("validation", func() {
+		It("should validate fallback configs", func() {
+			bad := cfg.WithFallback(scorer.Config{Client: secondary, Model: "no-such-model"})
+			Expect(bad.Validate()).To(MatchError(ContainSubstring("fallback 1")))
+		})
+
+		It("should reject nested fallbacks", func() {
+			nested := scorer.Config{Client: primary}.WithFallback(cfg)
+			Expect(nested.Validate()).To(MatchError(ContainSubstring("nested fallbacks")))
+		})
+
+		It("should require at least one backend", func() {
+			_, err := scorer.NewFallbackScorer()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,172 @@
+package scorer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// zeroTemperature stands for an explicit zero temperature in a chat completion
+// request. The client omits a zero Temperature, which would leave the server
+// default, so greedy decoding is sent as the smallest non-zero value instead;
+// AnthropicClient maps it back to zero.
+const zeroTemperature float32 = math.SmallestNonzeroFloat32
+
+// generationParams holds the resolved generation settings for one request
+type generationParams struct {
+	temperature     *float32 // nil leaves the model's default
+	maxOutputTokens int
+	reasoningEffort ReasoningEffort
+	logprobs        bool
+}
+
+// resolveGeneration merges config-level generation settings with per-request options
+func (s *scorer) resolveGeneration(options *scoringOptions) generationParams {
+	params := generationParams{
+		maxOutputTokens: s.config.MaxOutputTokens,
+		reasoningEffort: s.config.ReasoningEffort,
+	}
+	if s.config.Temperature != nil {
+		temperature := *s.config.Temperature
+		params.temperature = &temperature
+	}
+
+	if options != nil {
+		params.logprobs = options.logprobs
+		if options.temperature != nil {
+			params.temperature = options.temperature
+		}
+		if options.maxOutputTokens != 0 {
+			params.maxOutputTokens = options.maxOutputTokens
+		}
+		if options.reasoningEffort != "" {
+			params.reasoningEffort = options.reasoningEffort
+		}
+	}
+
+	return params
+}
+
+// applyGeneration translates generation settings into request fields for the
+// model's family. Reasoning models get max_completion_tokens and
+// reasoning_effort and never a temperature; other models get max_tokens and a
+// temperature when they support one. Logprobs are requested from every model
+// except reasoning models, which reject them. Unknown models are treated as
+// standard chat models.
+func applyGeneration(request *openai.ChatCompletionRequest, params generationParams) {
+	caps, known := LookupModel(request.Model)
+
+	maxTokens := params.maxOutputTokens
+	if known && caps.MaxOutputTokens > 0 && maxTokens > caps.MaxOutputTokens {
+		slog.Debug("Clamping output token limit to model maximum",
+			"model", request.Model,
+			"requested", maxTokens,
+			"max", caps.MaxOutputTokens)
+		maxTokens = caps.MaxOutputTokens
+	}
+
+	if known && caps.Reasoning {
+		request.MaxCompletionTokens = maxTokens
+		request.ReasoningEffort = string(params.reasoningEffort)
+		if params.temperature != nil {
+			slog.Debug("Dropping temperature for reasoning model", "model", request.Model)
+		}
+		if params.logprobs {
+			slog.Debug("Dropping logprobs for reasoning model", "model", request.Model)
+		}
+		return
+	}
+
+	if params.logprobs {
+		request.LogProbs = true
+		request.TopLogProbs = logprobCandidates
+	}
+
+	request.MaxTokens = maxTokens
+	if params.temperature != nil {
+		switch {
+		case known && !caps.SupportsTemperature:
+			slog.Debug("Dropping temperature for model without temperature support", "model", request.Model)
+		case *params.temperature == 0:
+			request.Temperature = zeroTemperature
+		default:
+			request.Temperature = *params.temperature
+		}
+	}
+}
+
+// foldSystemPrompt merges system messages into the first user message for
+// models that reject the system role
+func foldSystemPrompt(request *openai.ChatCompletionRequest) {
+	caps, known := LookupModel(request.Model)
+	if !known || !caps.NoSystemRole {
+		return
+	}
+
+	var system string
+	messages := make([]openai.ChatCompletionMessage, 0, len(request.Messages))
+	for _, msg := range request.Messages {
+		if msg.Role == openai.ChatMessageRoleSystem {
+			system += msg.Content + "\n\n"
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	for i := range messages {
+		if messages[i].Role == openai.ChatMessageRoleUser {
+			messages[i].Content = system + messages[i].Content
+			break
+		}
+	}
+	request.Messages = messages
+}
+
+// maxTemperature returns the highest sampling temperature the provider accepts
+func (c Config) maxTemperature() float32 {
+	if c.Provider == ProviderAnthropic {
+		return 1
+	}
+	return 2
+}
+
+// validateOptions checks per-request generation settings against the
+// provider's limits before any API call
+func (c Config) validateOptions(options *scoringOptions) error {
+	return validateGeneration(options.temperature, c.maxTemperature(), options.maxOutputTokens, options.reasoningEffort)
+}
+
+// validateGeneration checks generation settings shared by Config and options.
+// A nil temperature is unset and always valid.
+func validateGeneration(temperature *float32, maxTemperature float32, maxOutputTokens int, effort ReasoningEffort) error {
+	if temperature != nil && (*temperature < 0 || *temperature > maxTemperature) {
+		return fmt.Errorf("temperature must be between 0 and %v, got %v", maxTemperature, *temperature)
+	}
+	if maxOutputTokens < 0 {
+		return errors.New("MaxOutputTokens must be non-negative")
+	}
+	switch effort {
+	case "", ReasoningEffortLow, ReasoningEffortMedium, ReasoningEffortHigh:
+		return nil
+	default:
+		return fmt.Errorf("invalid reasoning effort: %s", effort)
+	}
+}
+
+// recordUsage records token consumption for a completion, keeping reasoning
+// tokens separate from visible completion tokens
+func (s *scorer) recordUsage(usage openai.Usage) {
+	if s.metrics == nil {
+		return
+	}
+
+	s.metrics.RecordTokensUsed("prompt", usage.PromptTokens)
+	s.metrics.RecordTokensUsed("completion", usage.CompletionTokens)
+	s.metrics.RecordTokensUsed("total", usage.TotalTokens)
+	if usage.CompletionTokensDetails != nil && usage.CompletionTokensDetails.ReasoningTokens > 0 {
+		s.metrics.RecordTokensUsed("reasoning", usage.CompletionTokensDetails.ReasoningTokens)
+	}
+}
@@ -0,0 +1,208 @@
+// Package scorer_test verifies generation parameter handling: temperature and
+// output limits for chat models, max_completion_tokens and reasoning_effort for
+// reasoning models, system prompt folding and reasoning token accounting.
+package scorer_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+)
+
+var _ = Describe("Generation parameters", func() {
+	var (
+		ctx    context.Context
+		client *mockScoringClient
+		items  []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[{"item_id":"1","score":65,"reason":"gig"}]}`
+			},
+		}
+		items = []scorer.TextItem{{ID: "1", Content: "Jazz at the Blue Note"}}
+	})
+
+	It("should send temperature and max_tokens to chat models", func() {
+		cfg := scorer.Config{Client: client, Model: openai.GPT4oMini}.
+			WithGeneration(0.2, 2_000, scorer.ReasoningEffortHigh)
+		s, err := scorer.NewScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := client.requests[0]
+		Expect(req.Temperature).To(BeNumerically("~", 0.2, 1e-6))
+		Expect(req.MaxTokens).To(Equal(2_000))
+		Expect(req.MaxCompletionTokens).To(BeZero())
+		Expect(req.ReasoningEffort).To(BeEmpty())
+	})
+
+	It("should send max_completion_tokens and reasoning_effort to reasoning models", func() {
+		cfg := scorer.Config{Client: client, Model: openai.O3Mini}.
+			WithGeneration(0.2, 2_000, scorer.ReasoningEffortLow)
+		s, err := scorer.NewScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := client.requests[0]
+		Expect(req.Temperature).To(BeZero())
+		Expect(req.MaxTokens).To(BeZero())
+		Expect(req.MaxCompletionTokens).To(Equal(2_000))
+		Expect(req.ReasoningEffort).To(Equal("low"))
+		Expect(req.Messages[0].Role).To(Equal(openai.ChatMessageRoleSystem))
+	})
+
+	It("should let per-request options override the config", func() {
+		cfg := scorer.Config{Client: client, Model: openai.O3Mini}.
+			WithGeneration(0, 2_000, scorer.ReasoningEffortLow)
+		s, err := scorer.NewScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items,
+			scorer.WithReasoningEffort(scorer.ReasoningEffortHigh),
+			scorer.WithMaxOutputTokens(500))
+		Expect(err).ToNot(HaveOccurred())
+
+		req := client.requests[0]
+		Expect(req.ReasoningEffort).To(Equal("high"))
+		Expect(req.MaxCompletionTokens).To(Equal(500))
+	})
+
+	It("should let a zero temperature override the config", func() {
+		cfg := scorer.Config{Client: client, Model: openai.GPT4oMini}.WithGeneration(0.8, 0, "")
+		s, err := scorer.NewScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items, scorer.WithTemperature(0))
+		Expect(err).ToNot(HaveOccurred())
+
+		// A zero float is omitted from the request body, so greedy decoding is sent as the smallest non-zero value
+		req := client.requests[0]
+		Expect(req.Temperature).To(BeNumerically(">", 0))
+		Expect(req.Temperature).To(BeNumerically("<", 1e-6))
+	})
+
+	It("should send a zero temperature set on the config", func() {
+		cfg := scorer.Config{Client: client, Model: openai.GPT4oMini}.WithTemperature(0)
+		s, err := scorer.NewScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := client.requests[0]
+		Expect(req.Temperature).To(BeNumerically(">", 0))
+		Expect(req.Temperature).To(BeNumerically("<", 1e-6))
+	})
+
+	It("should reject a config temperature out of range", func() {
+		_, err := scorer.NewScorer(scorer.Config{Client: client}.WithTemperature(2.5))
+		Expect(err).To(MatchError(ContainSubstring("temperature must be between 0 and 2")))
+	})
+
+	It("should leave the temperature unset when neither the config nor the request sets one", func() {
+		s, err := scorer.NewScorer(scorer.Config{Client: client, Model: openai.GPT4oMini})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests[0].Temperature).To(BeZero())
+	})
+
+	It("should clamp the output limit to the model maximum", func() {
+		s, err := scorer.NewScorer(scorer.Config{Client: client, Model: openai.GPT4Turbo})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items, scorer.WithMaxOutputTokens(50_000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests[0].MaxTokens).To(Equal(4_096))
+	})
+
+	It("should fold the system prompt into the user message for models without a system role", func() {
+		s, err := scorer.NewScorer(scorer.Config{Client: client, Model: openai.O1Mini})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := client.requests[0]
+		Expect(req.Messages).To(HaveLen(1))
+		Expect(req.Messages[0].Role).To(Equal(openai.ChatMessageRoleUser))
+		Expect(req.Messages[0].Content).To(ContainSubstring("matching this JSON schema"))
+		Expect(req.Messages[0].Content).To(ContainSubstring("Jazz at the Blue Note"))
+	})
+
+	It("should record reasoning tokens separately", func() {
+		client.usage = openai.Usage{
+			PromptTokens:     100,
+			CompletionTokens: 340,
+			TotalTokens:      440,
+			CompletionTokensDetails: &openai.CompletionTokensDetails{
+				ReasoningTokens: 300,
+			},
+		}
+		before := tokensUsed("reasoning")
+
+		s, err := scorer.NewScorer(scorer.Config{Client: client, Model: openai.O3Mini})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(tokensUsed("reasoning") - before).To(Equal(300.0))
+	})
+
+	Describe("validation", func() {
+		It("should reject out-of-range settings", func() {
+			base := scorer.NewDefaultConfig("test-key")
+			Expect(base.WithGeneration(2.5, 0, "").Validate()).To(MatchError(ContainSubstring("temperature")))
+			Expect(base.WithGeneration(0, -1, "").Validate()).To(MatchError(ContainSubstring("MaxOutputTokens")))
+			Expect(base.WithGeneration(0, 0, "extreme").Validate()).To(MatchError(ContainSubstring("reasoning effort")))
+			Expect(base.WithGeneration(1, 1_000, scorer.ReasoningEffortMedium).Validate()).To(Succeed())
+		})
+
+		It("should reject out-of-range per-request settings before calling the API", func() {
+			s, err := scorer.NewScorer(scorer.Config{Client: client})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s.ScoreTexts(ctx, items, scorer.WithTemperature(3))
+			Expect(err).To(MatchError(ContainSubstring("temperature")))
+			_, err = s.ScoreTexts(ctx, items, scorer.WithMaxOutputTokens(-1))
+			Expect(err).To(MatchError(ContainSubstring("MaxOutputTokens")))
+			_, err = s.ScoreTexts(ctx, items, scorer.WithReasoningEffort("extreme"))
+			Expect(err).To(MatchError(ContainSubstring("reasoning effort")))
+			Expect(client.requests).To(BeEmpty())
+		})
+	})
+})
+
+// tokensUsed reads the token counter for one token type from the default registry
+func tokensUsed(tokenType string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "text_scorer_api_tokens_used_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "type" && label.GetValue() == tokenType {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -3,6 +3,7 @@ package scorer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -24,6 +25,56 @@ func NewIntegratedScorer(cfg Config) (Scorer, error) {
 		return nil, err
 	}
 
+	scorer, err := newResilientScorer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chain fallback backends behind the primary, each with its own resilience stack
+	if len(cfg.Fallbacks) > 0 {
+		backends := []Backend{{
+			Name:   backendLabel(cfg.providerOrDefault(), modelOrDefault(cfg)),
+			Scorer: scorer,
+		}}
+		for i, fallbackCfg := range cfg.Fallbacks {
+			fallbackCfg = cfg.fallbackConfig(fallbackCfg)
+			fallback, err := newResilientScorer(fallbackCfg)
+			if err != nil {
+				return nil, fmt.Errorf("fallback %d: %w", i, err)
+			}
+			backends = append(backends, Backend{
+				Name:   backendLabel(fallbackCfg.providerOrDefault(), modelOrDefault(fallbackCfg)),
+				Scorer: fallback,
+			})
+		}
+
+		slog.Info("Enabling backend fallback", "backends", len(backends))
+		scorer, err = NewFallbackScorer(backends...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create integrated scorer with metrics
+	integrated := &IntegratedScorer{
+		baseScorer: scorer,
+		metrics:    NewMetricsRecorder(true).WithScale(cfg.resolveScale()),
+		config:     cfg,
+	}
+
+	slog.Info("Integrated scorer created",
+		"model", cfg.Model,
+		"max_concurrent", cfg.MaxConcurrent,
+		"circuit_breaker", cfg.EnableCircuitBreaker,
+		"retry", cfg.EnableRetry,
+		"fallbacks", len(cfg.Fallbacks))
+
+	return integrated, nil
+}
+
+// newResilientScorer creates a base scorer wrapped with the retry and circuit
+// breaker layers its config enables
+func newResilientScorer(cfg Config) (Scorer, error) {
 	// Create base scorer
 	baseScorer, err := NewScorer(cfg)
 	if err != nil {
@@ -61,20 +112,62 @@ func NewIntegratedScorer(cfg Config) (Scorer, error) {
 		scorer = NewCircuitBreakerScorer(scorer, cfg.CircuitBreakerConfig)
 	}
 
-	// Create integrated scorer with metrics
-	integrated := &IntegratedScorer{
-		baseScorer: scorer,
-		metrics:    NewMetricsRecorder(true),
-		config:     cfg,
+	return scorer, nil
+}
+
+// fallbackConfig returns a fallback's config with the primary's scale,
+// entities and evidence filled in where the fallback leaves them unset
+func (c Config) fallbackConfig(fallback Config) Config {
+	// Fallbacks score on the primary's scale unless they set the same one,
+	// and return the primary's entities and evidence unless they configure their own
+	if fallback.Scale == nil {
+		fallback.Scale = c.Scale
+	}
+	if fallback.Entities == nil {
+		fallback.Entities = c.Entities
 	}
+	if fallback.Evidence == nil {
+		fallback.Evidence = c.Evidence
+	}
+	return fallback
+}
 
-	slog.Info("Integrated scorer created",
-		"model", cfg.Model,
-		"max_concurrent", cfg.MaxConcurrent,
-		"circuit_breaker", cfg.EnableCircuitBreaker,
-		"retry", cfg.EnableRetry)
+// newPerCallScorer creates a base scorer whose client is wrapped with the
+// retry and circuit breaker layers its config enables. Modes such as ranking
+// and classification make many calls per request, so resilience applies to
+// each call rather than to the request as a whole, and each call falls back
+// through the configured fallback backends in order.
+func newPerCallScorer(cfg Config) (*scorer, error) {
+	base, err := NewScorer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := base.(*scorer)
 
-	return integrated, nil
+	if cfg.EnableRetry {
+		s.client = NewRetryWrapper(s.client, cfg.RetryConfig)
+	}
+	if cfg.EnableCircuitBreaker {
+		s.client = NewCircuitBreakerWrapper(s.client, cfg.CircuitBreakerConfig)
+	}
+
+	for i, fallbackCfg := range cfg.Fallbacks {
+		fallback, err := newPerCallScorer(cfg.fallbackConfig(fallbackCfg))
+		if err != nil {
+			return nil, fmt.Errorf("fallback %d: %w", i, err)
+		}
+		s.fallbacks = append(s.fallbacks, fallback)
+	}
+
+	return s, nil
+}
+
+// modelOrDefault returns the configured model or the provider default
+func modelOrDefault(cfg Config) string {
+	if cfg.Model != "" {
+		return cfg.Model
+	}
+	return defaultModel(cfg.Provider)
 }
 
 // ScoreTexts implements TextScorer with full integration
@@ -102,7 +195,7 @@ func (s *IntegratedScorer) ScoreTextsWithOptions(ctx context.Context, items []Te
 	}
 	model := options.model
 	if model == "" {
-		model = openai.GPT4oMini
+		model = defaultModel(s.config.Provider)
 	}
 
 	// Call underlying scorer
@@ -112,19 +205,15 @@ func (s *IntegratedScorer) ScoreTextsWithOptions(ctx context.Context, items []Te
 	duration := time.Since(start).Seconds()
 	s.metrics.RecordRequestDuration(duration, model)
 
+	// Batches that succeeded are recorded and returned even when others failed
+	s.metrics.recordScores(results)
 	if err != nil {
 		s.metrics.RecordRequest("error", model)
 		s.metrics.RecordError(classifyError(err))
-		return nil, err
+		return results, err
 	}
 
 	s.metrics.RecordRequest("success", model)
-	s.metrics.RecordItemsScored(len(results))
-
-	// Record score distribution
-	for _, result := range results {
-		s.metrics.RecordScore(result.Score)
-	}
 
 	return results, nil
 }
@@ -140,6 +229,7 @@ func (s *IntegratedScorer) GetHealth(ctx context.Context) HealthStatus {
 		"metrics_enabled":         true,
 		"model":                   s.config.Model,
 		"max_concurrent":          s.config.MaxConcurrent,
+		"fallbacks":               len(s.config.Fallbacks),
 	}
 
 	return baseHealth
@@ -198,6 +288,19 @@ func classifyError(err error) string {
 		return "cancelled"
 	}
 
+	if errors.Is(err, ErrOutputTruncated) {
+		return "truncated"
+	}
+
+	var refusal *RefusalError
+	if errors.As(err, &refusal) {
+		return "refused"
+	}
+
+	if errors.Is(err, ErrNoChoices) {
+		return "empty_response"
+	}
+
 	if errors.Is(err, gobreaker.ErrOpenState) {
 		return "circuit_open"
 	}
@@ -237,12 +340,8 @@ func (m *metricsScorer) ScoreTextsWithOptions(ctx context.Context, items []TextI
 
 	if err != nil {
 		m.metrics.RecordError(classifyError(err))
-	} else {
-		m.metrics.RecordItemsScored(len(results))
-		for _, result := range results {
-			m.metrics.RecordScore(result.Score)
-		}
 	}
+	m.metrics.recordScores(results)
 
 	return results, err
 }
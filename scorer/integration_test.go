@@ -118,6 +118,7 @@ var _ = Describe("Integration", func() {
 				// Create scorer with retry and circuit breaker
 				cfg := scorer.Config{
 					APIKey:               "test",
+					Client:               mockClient,
 					EnableRetry:          true,
 					EnableCircuitBreaker: true,
 					RetryConfig: &scorer.RetryConfig{
@@ -133,11 +134,14 @@ var _ = Describe("Integration", func() {
 					},
 				}
 
-				// This would use the mock client in a real implementation
-				// For now, we're testing the configuration works
 				s, err := scorer.NewIntegratedScorer(cfg)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(s).ToNot(BeNil())
+
+				results, err := s.ScoreTexts(ctx, []scorer.TextItem{{ID: "1", Content: "test"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Score).To(Equal(75))
+				Expect(mockClient.calls).To(Equal(3))
 			})
 
 			It("should trip circuit breaker after persistent failures", func() {
@@ -0,0 +1,225 @@
+package scorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+var judgeSystemPrompt string
+var judgePrompts map[JudgeCriterion]string
+var judgePromptError error
+
+func init() {
+	systemBytes, err := promptFS.ReadFile("prompts/judge_system_prompt.txt")
+	if err != nil {
+		judgePromptError = fmt.Errorf("failed to load judge system prompt: %w", err)
+		return
+	}
+	judgeSystemPrompt = string(systemBytes)
+
+	judgePrompts = make(map[JudgeCriterion]string)
+	for _, criterion := range []JudgeCriterion{JudgeCorrectness, JudgeFaithfulness} {
+		promptBytes, err := promptFS.ReadFile(fmt.Sprintf("prompts/judge_%s_prompt.txt", criterion))
+		if err != nil {
+			judgePromptError = fmt.Errorf("failed to load judge %s prompt: %w", criterion, err)
+			return
+		}
+		judgePrompts[criterion] = string(promptBytes)
+	}
+}
+
+// JudgeCriterion selects what a judge scores candidate answers on
+type JudgeCriterion string
+
+const (
+	// JudgeCorrectness scores whether the candidate states the same facts as the reference answer
+	JudgeCorrectness JudgeCriterion = "correctness"
+	// JudgeFaithfulness scores whether every claim in the candidate is supported by the reference
+	JudgeFaithfulness JudgeCriterion = "faithfulness"
+)
+
+// JudgeItem is a candidate answer to score against a reference
+type JudgeItem struct {
+	ID        string                 // Unique identifier for the item
+	Question  string                 // Question the candidate answers (optional)
+	Candidate string                 // Answer being judged
+	Reference string                 // Gold answer, or source material for faithfulness
+	Metadata  map[string]interface{} // Optional metadata, shown to the model as for TextItem
+}
+
+// JudgedItem is a judge item with its score
+type JudgedItem struct {
+	Item    JudgeItem // Original judge item
+	Score   int       // Score on the configured scale (0-100 by default)
+	Value   float64   // Exact score on the configured scale
+	Reason  string    // AI explanation comparing the candidate with the reference
+	Backend string    // Backend that produced the score, as "provider/model"
+	Missing bool      // The item was not scored, even when re-asked; Score and Value are unset
+	Err     error     // Why a Missing item was not scored: ErrItemMissing, a *RefusalError or a *BatchError
+
+	// Logprob results, set only when judging with logprobs and the backend returns them
+	ExpectedScore float64 // Probability-weighted score on the configured scale
+	Confidence    float64 // Probability of the sampled score among candidate scores, 0-1
+}
+
+// Judge scores candidate answers against reference answers
+type Judge interface {
+	// Judge scores every item, returning results in input order. When some
+	// batches fail, the results are still returned alongside the error, with
+	// the failed batches' items Missing.
+	Judge(ctx context.Context, items []JudgeItem, opts ...ScoringOption) ([]JudgedItem, error)
+}
+
+// judge scores judge items through a scorer, replacing the scoring prompts
+// with the criterion's built-in judge prompts
+type judge struct {
+	scorer    *scorer
+	config    Config // Caller's config, which candidate lengths are validated against
+	criterion JudgeCriterion
+	system    string // Judge system prompt with the scale filled in
+	prompt    string // Criterion prompt with the scale filled in
+}
+
+// NewJudge creates a judge for a criterion. Each item's question, reference
+// and candidate are sent together, and the built-in prompt for the criterion
+// tells the model how to score the candidate. Scoring runs through the normal
+// pipeline, so the config's scale, batching, concurrency, retry, circuit
+// breaker and logprobs settings all apply; WithPromptTemplate replaces the
+// built-in prompt for custom criteria. The rubric, entities and evidence
+// settings belong to the scoring prompts and are not used, and
+// MaxContentLength applies to each candidate rather than to the combined
+// question, reference and candidate.
+func NewJudge(cfg Config, criterion JudgeCriterion) (Judge, error) {
+	if judgePromptError != nil {
+		return nil, judgePromptError
+	}
+
+	if _, ok := judgePrompts[criterion]; !ok {
+		return nil, fmt.Errorf("unsupported judge criterion: %s", criterion)
+	}
+
+	s, err := newPerCallScorer(judgeConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	// The judge prompts describe the configured scale, as the scoring prompts do
+	scale := cfg.resolveScale()
+	system, err := scale.render(judgeSystemPrompt)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := scale.render(judgePrompts[criterion])
+	if err != nil {
+		return nil, err
+	}
+
+	return &judge{scorer: s, config: cfg, criterion: criterion, system: system, prompt: prompt}, nil
+}
+
+// judgeConfig returns cfg, and each of its fallbacks, without the rubric,
+// entities and evidence settings, which only apply to scoring prompts. The
+// content length limit is lifted because judge items combine the question
+// and reference with the candidate; Judge checks candidates on their own.
+func judgeConfig(cfg Config) Config {
+	cfg.Rubric, cfg.Entities, cfg.Evidence = nil, nil, nil
+	cfg.MaxContentLength = math.MaxInt
+	if len(cfg.Fallbacks) > 0 {
+		fallbacks := make([]Config, len(cfg.Fallbacks))
+		for i, fallback := range cfg.Fallbacks {
+			fallbacks[i] = judgeConfig(fallback)
+		}
+		cfg.Fallbacks = fallbacks
+	}
+	return cfg
+}
+
+// Judge implements Judge
+func (j *judge) Judge(ctx context.Context, items []JudgeItem, opts ...ScoringOption) ([]JudgedItem, error) {
+	if items == nil {
+		return nil, errors.New("items cannot be nil")
+	}
+
+	if len(items) == 0 {
+		return []JudgedItem{}, nil
+	}
+
+	textItems := make([]TextItem, len(items))
+	for i, item := range items {
+		if strings.TrimSpace(item.Candidate) == "" {
+			return nil, fmt.Errorf("judge item %s at index %d has no candidate answer", item.ID, i)
+		}
+		if strings.TrimSpace(item.Reference) == "" {
+			return nil, fmt.Errorf("judge item %s at index %d has no reference", item.ID, i)
+		}
+		// Only the candidate is held to the length limit; a long reference
+		// or question must not fail the request
+		if err := j.config.validateItem(i, TextItem{ID: item.ID, Content: item.Candidate}); err != nil {
+			return nil, err
+		}
+		textItems[i] = TextItem{ID: item.ID, Content: item.content(), Metadata: item.Metadata}
+	}
+
+	judgeOpts := append([]ScoringOption{j.prompts()}, opts...)
+
+	slog.Info("Judging candidate answers", "items", len(items), "criterion", j.criterion)
+
+	start := time.Now()
+	j.scorer.metrics.RecordBatchSize(len(items))
+
+	scored, err := j.scorer.ScoreTextsWithOptions(ctx, textItems, judgeOpts...)
+	j.scorer.metrics.recordOutcome(j.model(judgeOpts), start, err)
+	if scored == nil {
+		return nil, err
+	}
+
+	results := make([]JudgedItem, len(scored))
+	for i, result := range scored {
+		results[i] = JudgedItem{
+			Item:          items[i],
+			Score:         result.Score,
+			Value:         result.Value,
+			Reason:        result.Reason,
+			Backend:       result.Backend,
+			Missing:       result.Missing,
+			Err:           result.Err,
+			ExpectedScore: result.ExpectedScore,
+			Confidence:    result.Confidence,
+		}
+	}
+	// Items of failed batches are Missing, and err joins their batch errors
+	return results, err
+}
+
+// prompts sets the criterion's judge prompt and system prompt
+func (j *judge) prompts() ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.promptText = j.prompt
+		opts.systemPrompt = j.system
+	}
+}
+
+// model returns the model a request with these options runs on
+func (j *judge) model(opts []ScoringOption) string {
+	options := &scoringOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return j.scorer.config.resolveModel(options)
+}
+
+// content lays out the question, reference and candidate for the prompt
+func (j JudgeItem) content() string {
+	var sb strings.Builder
+	if j.Question != "" {
+		sb.WriteString("Question:\n" + j.Question + "\n\n")
+	}
+	sb.WriteString("Reference:\n" + j.Reference + "\n\n")
+	sb.WriteString("Candidate answer:\n" + j.Candidate)
+	return sb.String()
+}
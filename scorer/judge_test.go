@@ -0,0 +1,203 @@
+// Package scorer_test covers reference-guided judging: the built-in judge
+// prompts, how items are laid out for the model, and item validation.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Judge", func() {
+	var (
+		ctx   context.Context
+		items []scorer.JudgeItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.JudgeItem{
+			{
+				ID:        "q1",
+				Question:  "When does the Brighton Festival start?",
+				Candidate: "It starts on the first Saturday of May.",
+				Reference: "Brighton Festival opens on the first Saturday in May.",
+			},
+			{
+				ID:        "q2",
+				Candidate: "The gallery is open on Mondays.",
+				Reference: "The gallery is closed on Mondays.",
+			},
+		}
+	})
+
+	It("should send the question, reference and candidate with the correctness prompt", func() {
+		fake := scorertest.NewFakeClient()
+		judge, err := scorer.NewJudge(scorer.Config{Client: fake}, scorer.JudgeCorrectness)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = judge.Judge(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := fake.Requests()[0]
+		Expect(req.Messages[0].Content).To(ContainSubstring("impartial evaluator"))
+		prompt := req.Messages[1].Content
+		Expect(prompt).To(ContainSubstring("Judge the correctness of each candidate answer"))
+		Expect(prompt).To(ContainSubstring("\n90-100: Candidate states the same facts"))
+		Expect(prompt).To(ContainSubstring("Item 1 (ID: q1):\nQuestion:\nWhen does the Brighton Festival start?\n\n" +
+			"Reference:\nBrighton Festival opens on the first Saturday in May.\n\n" +
+			"Candidate answer:\nIt starts on the first Saturday of May."))
+		Expect(prompt).To(ContainSubstring("Item 2 (ID: q2):\nReference:\n"))
+	})
+
+	It("should use the faithfulness prompt", func() {
+		fake := scorertest.NewFakeClient()
+		judge, err := scorer.NewJudge(scorer.Config{Client: fake}, scorer.JudgeFaithfulness)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = judge.Judge(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()[0].Messages[1].Content).To(ContainSubstring("Judge the faithfulness"))
+	})
+
+	It("should map scores back to judge items in input order", func() {
+		fake := scorertest.NewFakeClient(
+			scorertest.WithScores(map[string]int{"q1": 95, "q2": 5}),
+			scorertest.WithReasons(map[string]string{"q2": "contradicts the reference"}),
+		)
+		judge, err := scorer.NewJudge(scorer.Config{Client: fake, Model: "gpt-4o"}, scorer.JudgeCorrectness)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := judge.Judge(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].Item).To(Equal(items[0]))
+		Expect(results[0].Score).To(Equal(95))
+		Expect(results[0].Backend).To(Equal("openai/gpt-4o"))
+		Expect(results[1].Score).To(Equal(5))
+		Expect(results[1].Value).To(Equal(5.0))
+		Expect(results[1].Reason).To(Equal("contradicts the reference"))
+	})
+
+	It("should mark items the model never scores as missing", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithRefusals(map[string]string{"q2": "No."}))
+		fake.FailAlways(scorertest.Fault{MissingIDs: []string{"q1"}})
+		judge, err := scorer.NewJudge(scorer.Config{Client: fake}, scorer.JudgeCorrectness)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := judge.Judge(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Missing).To(BeTrue())
+		Expect(results[0].Err).To(MatchError(scorer.ErrItemMissing))
+		Expect(results[1].Missing).To(BeTrue())
+		var refusal *scorer.RefusalError
+		Expect(errors.As(results[1].Err, &refusal)).To(BeTrue())
+		Expect(refusal.ItemID).To(Equal("q2"))
+	})
+
+	It("should score on the configured scale", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"q1": 100, "q2": 0}))
+		judge, err := scorer.NewJudge(scorer.Config{Client: fake}.WithScale(scorer.LikertScale), scorer.JudgeCorrectness)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := judge.Judge(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(5))
+		Expect(results[1].Score).To(Equal(1))
+		schema, err := json.Marshal(fake.Requests()[0].ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).To(ContainSubstring("Score from 1 to 5"))
+
+		req := fake.Requests()[0]
+		Expect(req.Messages[0].Content).To(ContainSubstring("integers between 1 and 5, where 1 means the candidate fails completely"))
+		Expect(req.Messages[1].Content).To(ContainSubstring("\n5: Candidate states the same facts"))
+		Expect(req.Messages[1].Content).To(ContainSubstring("\n1: Candidate is wrong"))
+		Expect(req.Messages[0].Content + req.Messages[1].Content).ToNot(ContainSubstring("100"))
+	})
+
+	It("should let a custom prompt replace the built-in one", func() {
+		fake := scorertest.NewFakeClient()
+		judge, err := scorer.NewJudge(scorer.Config{Client: fake}, scorer.JudgeCorrectness)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = judge.Judge(ctx, items, scorer.WithPromptTemplate("Score tone against the reference:\n%s"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()[0].Messages[1].Content).To(HavePrefix("Score tone against the reference"))
+	})
+
+	It("should validate judge items", func() {
+		judge, err := scorer.NewJudge(scorer.Config{Client: scorertest.NewFakeClient()}, scorer.JudgeCorrectness)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = judge.Judge(ctx, nil)
+		Expect(err).To(MatchError("items cannot be nil"))
+
+		results, err := judge.Judge(ctx, []scorer.JudgeItem{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(BeEmpty())
+
+		_, err = judge.Judge(ctx, []scorer.JudgeItem{{ID: "a", Reference: "ref"}})
+		Expect(err).To(MatchError(ContainSubstring("has no candidate answer")))
+
+		_, err = judge.Judge(ctx, []scorer.JudgeItem{{ID: "a", Candidate: "answer"}})
+		Expect(err).To(MatchError(ContainSubstring("has no reference")))
+
+		_, err = judge.Judge(ctx, []scorer.JudgeItem{{Candidate: "answer", Reference: "ref"}})
+		Expect(err).To(MatchError(ContainSubstring("empty ID")))
+	})
+
+	It("should hold only the candidate to the content length limit", func() {
+		fake := scorertest.NewFakeClient()
+		judge, err := scorer.NewJudge(scorer.Config{Client: fake, MaxContentLength: 60}, scorer.JudgeFaithfulness)
+		Expect(err).ToNot(HaveOccurred())
+
+		longReference := strings.Repeat("The gallery opens at ten every day. ", 10)
+		results, err := judge.Judge(ctx, []scorer.JudgeItem{
+			{ID: "a", Question: "When does the gallery open?", Candidate: "At ten.", Reference: longReference},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Missing).To(BeFalse())
+
+		_, err = judge.Judge(ctx, []scorer.JudgeItem{
+			{ID: "b", Candidate: strings.Repeat("ten ", 20), Reference: "Opens at ten."},
+		})
+		Expect(err).To(MatchError(scorer.ErrContentTooLong))
+		Expect(err).To(MatchError(ContainSubstring("item b at index 0")))
+		Expect(fake.Requests()).To(HaveLen(1))
+	})
+
+	It("should not use the rubric, entities or evidence settings", func() {
+		fake := scorertest.NewFakeClient()
+		cfg := scorer.Config{
+			Client:   fake,
+			Rubric:   &scorer.Rubric{Dimensions: []scorer.Dimension{{Name: "tone", Min: 1, Max: 5}}},
+			Entities: &scorer.EntityConfig{},
+			Evidence: &scorer.EvidenceConfig{},
+		}
+		judge, err := scorer.NewJudge(cfg, scorer.JudgeCorrectness)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := judge.Judge(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+
+		schema, err := json.Marshal(fake.Requests()[0].ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).ToNot(ContainSubstring(`"dimensions"`))
+		Expect(string(schema)).ToNot(ContainSubstring(`"entities"`))
+		Expect(string(schema)).ToNot(ContainSubstring(`"evidence"`))
+	})
+
+	It("should reject unknown criteria", func() {
+		_, err := scorer.NewJudge(scorer.Config{Client: scorertest.NewFakeClient()}, "tone")
+		Expect(err).To(MatchError("unsupported judge criterion: tone"))
+	})
+})
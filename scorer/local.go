@@ -0,0 +1,216 @@
+package scorer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// DefaultLocalBaseURL is the OpenAI-compatible endpoint of a default Ollama install
+const DefaultLocalBaseURL = "http://localhost:11434/v1"
+
+// ErrSchemaMismatch is returned when a non-strict response does not match the expected schema
+var ErrSchemaMismatch = errors.New("response does not match the expected JSON schema")
+
+// NewLocalConfig creates a config for a self-hosted OpenAI-compatible server such
+// as Ollama or the llama.cpp server. No API key is needed; the output mode is
+// negotiated automatically unless set explicitly with WithOutputMode.
+func NewLocalConfig(baseURL, model string) Config {
+	if model == "" {
+		panic("model is required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultLocalBaseURL
+	}
+
+	return Config{
+		Provider:      ProviderLocal,
+		BaseURL:       baseURL,
+		Model:         model,
+		MaxConcurrent: 1,
+		Timeout:       30 * time.Second,
+	}
+}
+
+// WithOutputMode declares how the server supports structured output
+func (c Config) WithOutputMode(mode OutputMode) Config {
+	c.OutputMode = mode
+	return c
+}
+
+// isValidOutputMode checks if the output mode is supported
+func isValidOutputMode(mode OutputMode) bool {
+	switch mode {
+	case OutputModeAuto, OutputModeJSONSchema, OutputModeJSONObject, OutputModeGrammar, OutputModeText:
+		return true
+	default:
+		return false
+	}
+}
+
+// outputMode returns the mode to use for the next request to the model.
+// Explicit modes are used as-is. Auto mode remembers any downgrade negotiated
+// with the server, then uses the model's catalog capabilities, and otherwise
+// starts with strict JSON schema. Anthropic always receives the schema as a
+// forced tool, so catalog entries do not apply to it.
+func (s *scorer) outputMode(model string) OutputMode {
+	if s.config.OutputMode != OutputModeAuto {
+		return s.config.OutputMode
+	}
+	if mode, ok := s.detectedModes.Load(model); ok {
+		return mode.(OutputMode)
+	}
+	if s.config.Provider != ProviderAnthropic {
+		if caps, ok := LookupModel(model); ok {
+			return caps.OutputMode()
+		}
+	}
+	return OutputModeJSONSchema
+}
+
+// downgradeMode returns the next less demanding output mode to try after the
+// server rejected the given one
+func downgradeMode(mode OutputMode) (OutputMode, bool) {
+	switch mode {
+	case OutputModeJSONSchema:
+		return OutputModeJSONObject, true
+	case OutputModeJSONObject:
+		return OutputModeText, true
+	default:
+		return mode, false
+	}
+}
+
+// negotiatesOutputMode reports whether a rejected output mode is downgraded
+// and remembered for the model. Only auto mode negotiates, and only for local
+// servers and models missing from the catalog, whose support is unknown;
+// known hosted models keep their catalog mode, so an unrelated request error
+// cannot move them to a weaker mode for good.
+func (s *scorer) negotiatesOutputMode(model string) bool {
+	if s.config.OutputMode != OutputModeAuto {
+		return false
+	}
+	switch s.config.Provider {
+	case ProviderLocal:
+		return true
+	case ProviderAnthropic:
+		return false
+	}
+	_, known := LookupModel(model)
+	return !known
+}
+
+// isResponseFormatRejection reports whether an error is the server refusing
+// the requested response_format rather than a general failure: a client error
+// or not-implemented status whose message names response_format or json_schema
+func isResponseFormatRejection(err error) bool {
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.HTTPStatusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusNotImplemented:
+		message := strings.ToLower(apiErr.Message)
+		return strings.Contains(message, "response_format") ||
+			strings.Contains(message, "json_schema")
+	default:
+		return false
+	}
+}
+
+// schemaInstructions describes the expected JSON shape for modes where the
+// server does not receive the schema itself
+func schemaInstructions(schema *jsonschema.Definition) (string, error) {
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON schema: %w", err)
+	}
+	return "Respond with a single JSON object only, no prose or markdown, matching this JSON schema exactly:\n" +
+		string(schemaJSON), nil
+}
+
+// validateAgainstSchema checks a response that was not produced under strict
+// schema enforcement before it is trusted
+func validateAgainstSchema(schema *jsonschema.Definition, content string) error {
+	var data any
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		return err
+	}
+	if !jsonschema.Validate(*schema, data) {
+		return ErrSchemaMismatch
+	}
+	return nil
+}
+
+// grammarDoer rewrites strict json_schema response formats into the llama.cpp
+// server's grammar extension: a json_object response format plus a top-level
+// json_schema field, which the server compiles into a GBNF grammar
+type grammarDoer struct {
+	doer openai.HTTPDoer
+}
+
+// Do rewrites the request body when it carries a json_schema response format
+func (d *grammarDoer) Do(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Method != http.MethodPost {
+		return d.doer.Do(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	rewritten, err := rewriteForGrammar(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(rewritten))
+	req.ContentLength = int64(len(rewritten))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(rewritten)), nil
+	}
+	return d.doer.Do(req)
+}
+
+// rewriteForGrammar moves a json_schema response format into the grammar extension fields
+func rewriteForGrammar(body []byte) ([]byte, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return body, nil
+	}
+
+	raw, ok := payload["response_format"]
+	if !ok {
+		return body, nil
+	}
+
+	var format struct {
+		Type       string `json:"type"`
+		JSONSchema struct {
+			Schema json.RawMessage `json:"schema"`
+		} `json:"json_schema"`
+	}
+	if err := json.Unmarshal(raw, &format); err != nil || format.Type != string(openai.ChatCompletionResponseFormatTypeJSONSchema) {
+		return body, nil
+	}
+
+	payload["response_format"] = json.RawMessage(`{"type":"json_object"}`)
+	payload["json_schema"] = format.JSONSchema.Schema
+
+	rewritten, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite request for grammar output: %w", err)
+	}
+	return rewritten, nil
+}
@@ -0,0 +1,233 @@
+// Package scorer_test exercises the local-model backend against an httptest
+// server that mimics Ollama and llama.cpp quirks: rejecting strict JSON schema
+// output, answering in JSON mode and accepting grammar-constrained requests.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+)
+
+var _ = Describe("Local backend", func() {
+	var (
+		ctx           context.Context
+		server        *httptest.Server
+		mu            sync.Mutex
+		requests      []map[string]interface{}
+		rejectSchema  bool
+		rejectStatus  int
+		rejectMessage string
+		replyContent  string
+		itemsToScore  []scorer.TextItem
+		formatOfCalls func() []string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests = nil
+		rejectSchema = false
+		rejectStatus = http.StatusBadRequest
+		rejectMessage = "response_format type json_schema is not supported"
+		replyContent = `{"version":"1.0","scores":[{"item_id":"1","score":64,"reason":"mentions a market"}]}`
+		itemsToScore = []scorer.TextItem{{ID: "1", Content: "Farmers market every Saturday on the green"}}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var payload map[string]interface{}
+			_ = json.Unmarshal(body, &payload)
+
+			mu.Lock()
+			requests = append(requests, payload)
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+			format, _ := payload["response_format"].(map[string]interface{})
+			if rejectSchema && format["type"] == "json_schema" {
+				w.WriteHeader(rejectStatus)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"error": map[string]interface{}{"message": rejectMessage, "type": "invalid_request_error"},
+				})
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+				Choices: []openai.ChatCompletionChoice{
+					{Message: openai.ChatCompletionMessage{
+						Role:    openai.ChatMessageRoleAssistant,
+						Content: replyContent,
+					}},
+				},
+			})
+		}))
+
+		formatOfCalls = func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			var formats []string
+			for _, req := range requests {
+				format, _ := req["response_format"].(map[string]interface{})
+				formats = append(formats, format["type"].(string))
+			}
+			return formats
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewLocalConfig", func() {
+		It("should validate without an API key", func() {
+			cfg := scorer.NewLocalConfig(server.URL+"/v1", "llama3.1")
+			Expect(cfg.Validate()).To(Succeed())
+		})
+
+		It("should default to the Ollama endpoint", func() {
+			cfg := scorer.NewLocalConfig("", "llama3.1")
+			Expect(cfg.BaseURL).To(Equal(scorer.DefaultLocalBaseURL))
+		})
+
+		It("should reject unknown output modes", func() {
+			cfg := scorer.NewLocalConfig("", "llama3.1").WithOutputMode("xml")
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("unsupported output mode")))
+		})
+	})
+
+	Context("when the server rejects strict JSON schema output", func() {
+		BeforeEach(func() {
+			rejectSchema = true
+		})
+
+		It("should fall back to JSON mode and remember the downgrade", func() {
+			s, err := scorer.NewScorer(scorer.NewLocalConfig(server.URL+"/v1", "llama3.1"))
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, itemsToScore)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Score).To(Equal(64))
+
+			_, err = s.ScoreTexts(ctx, itemsToScore)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(formatOfCalls()).To(Equal([]string{"json_schema", "json_object", "json_object"}))
+
+			mu.Lock()
+			defer mu.Unlock()
+			messages := requests[1]["messages"].([]interface{})
+			system := messages[0].(map[string]interface{})["content"].(string)
+			Expect(system).To(ContainSubstring("matching this JSON schema"))
+		})
+
+		It("should reject JSON mode output that does not match the schema", func() {
+			replyContent = `{"version":"1.0","scores":[{"item_id":"1","score":"high"}]}`
+
+			s, err := scorer.NewScorer(scorer.NewLocalConfig(server.URL+"/v1", "llama3.1"))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s.ScoreTexts(ctx, itemsToScore)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should not fall back when the mode is declared explicitly", func() {
+			cfg := scorer.NewLocalConfig(server.URL+"/v1", "llama3.1").WithOutputMode(scorer.OutputModeJSONSchema)
+			s, err := scorer.NewScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s.ScoreTexts(ctx, itemsToScore)
+			var apiErr *openai.APIError
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.HTTPStatusCode).To(Equal(http.StatusBadRequest))
+			Expect(formatOfCalls()).To(Equal([]string{"json_schema"}))
+		})
+	})
+
+	Context("when the server fails a strict JSON schema request for another reason", func() {
+		BeforeEach(func() {
+			rejectSchema = true
+		})
+
+		DescribeTable("should return the error without changing mode",
+			func(status int, message string) {
+				rejectStatus, rejectMessage = status, message
+				s, err := scorer.NewScorer(scorer.NewLocalConfig(server.URL+"/v1", "llama3.1"))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = s.ScoreTexts(ctx, itemsToScore)
+				var apiErr *openai.APIError
+				Expect(errors.As(err, &apiErr)).To(BeTrue())
+				Expect(apiErr.HTTPStatusCode).To(Equal(status))
+				Expect(formatOfCalls()).To(Equal([]string{"json_schema"}))
+			},
+			Entry("a schema validation error", http.StatusBadRequest, "input failed schema validation: score is required"),
+			Entry("a server error", http.StatusInternalServerError, "response_format handler crashed"),
+		)
+	})
+
+	Context("when an OpenAI-compatible server rejects strict JSON schema output", func() {
+		BeforeEach(func() {
+			rejectSchema = true
+		})
+
+		It("should keep the catalog mode for known models", func() {
+			cfg := scorer.Config{APIKey: "test", BaseURL: server.URL + "/v1", Model: openai.GPT4oMini}
+			s, err := scorer.NewScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s.ScoreTexts(ctx, itemsToScore)
+			Expect(err).To(MatchError(ContainSubstring("json_schema is not supported")))
+			Expect(formatOfCalls()).To(Equal([]string{"json_schema"}))
+		})
+
+		It("should negotiate the mode for models missing from the catalog", func() {
+			cfg := scorer.Config{APIKey: "test", BaseURL: server.URL + "/v1", Model: "acme-finetune-1"}
+			s, err := scorer.NewScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, itemsToScore)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Score).To(Equal(64))
+			Expect(formatOfCalls()).To(Equal([]string{"json_schema", "json_object"}))
+		})
+	})
+
+	Context("with a declared JSON mode", func() {
+		It("should validate the response locally", func() {
+			replyContent = `{"version":"1.0","scores":[{"item_id":"1","reason":"no score"}]}`
+			cfg := scorer.NewLocalConfig(server.URL+"/v1", "llama3.1").WithOutputMode(scorer.OutputModeJSONObject)
+			s, err := scorer.NewScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s.ScoreTexts(ctx, itemsToScore)
+			Expect(errors.Is(err, scorer.ErrSchemaMismatch)).To(BeTrue())
+		})
+	})
+
+	Context("with grammar-constrained output", func() {
+		It("should send the schema through the server's grammar extension", func() {
+			cfg := scorer.NewLocalConfig(server.URL+"/v1", "qwen2.5").WithOutputMode(scorer.OutputModeGrammar)
+			s, err := scorer.NewScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, itemsToScore)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Score).To(Equal(64))
+
+			Expect(formatOfCalls()).To(Equal([]string{"json_object"}))
+			mu.Lock()
+			defer mu.Unlock()
+			schema := requests[0]["json_schema"].(map[string]interface{})
+			Expect(schema["properties"]).To(HaveKey("scores"))
+		})
+	})
+})
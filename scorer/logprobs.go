@@ -0,0 +1,146 @@
+package scorer
+
+import (
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// logprobCandidates is the number of alternative tokens requested at each position
+const logprobCandidates = 5
+
+// scoreLiteral matches a score value in the response JSON. Keys inside string
+// values are escaped, so only real score fields match.
+var scoreLiteral = regexp.MustCompile(`"score"\s*:\s*(-?\d+(?:\.\d+)?)`)
+
+// WithLogprobs requests token logprobs for this request so each result gets an
+// ExpectedScore and Confidence. Rubric scoring and reasoning models skip them.
+func WithLogprobs() ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.logprobs = true
+	}
+}
+
+// logprobScore is the score distribution read from one item's score token
+type logprobScore struct {
+	expected   float64
+	confidence float64
+}
+
+// applyExpectedScores sets ExpectedScore and Confidence on results whose score
+// token can be found in the logprobs. Results keep zero values when it cannot,
+// for example when the score spans several tokens.
+func (s *scorer) applyExpectedScores(results []ScoredItem, logprobs *openai.LogProbs, scores []scaledScoreItem) {
+	distributions := logprobScores(logprobs, scores, s.config.resolveScale())
+
+	for i := range results {
+		if d, ok := distributions[results[i].Item.ID]; ok {
+			results[i].ExpectedScore = d.expected
+			results[i].Confidence = d.confidence
+		}
+	}
+
+	slog.Debug("Computed expected scores from logprobs",
+		"items", len(results),
+		"with_logprobs", len(distributions))
+}
+
+// logprobScores locates each score value in the token stream and reads
+// the candidate scores at that position. The n-th score field in the content
+// belongs to the n-th parsed score, which is checked before it is used.
+func logprobScores(logprobs *openai.LogProbs, scores []scaledScoreItem, scale ScoreScale) map[string]logprobScore {
+	tokens := logprobs.Content
+
+	var text strings.Builder
+	starts := make([]int, len(tokens))
+	for i, token := range tokens {
+		starts[i] = text.Len()
+		text.WriteString(token.Token)
+	}
+	content := text.String()
+
+	distributions := make(map[string]logprobScore)
+	for n, match := range scoreLiteral.FindAllStringSubmatchIndex(content, -1) {
+		if n >= len(scores) {
+			break
+		}
+
+		literal := content[match[2]:match[3]]
+		value, err := strconv.ParseFloat(literal, 64)
+		if err != nil || value != scores[n].Score {
+			slog.Debug("Score tokens do not line up with parsed scores, skipping logprobs")
+			return distributions
+		}
+
+		t := tokenAt(starts, match[2])
+		if t < 0 || strings.TrimSpace(tokens[t].Token) != literal {
+			slog.Debug("Score spans several tokens, skipping logprobs", "item_id", scores[n].ItemID)
+			continue
+		}
+
+		if d, ok := candidateScores(tokens[t], scale); ok {
+			distributions[scores[n].ItemID] = d
+		}
+	}
+
+	return distributions
+}
+
+// tokenAt returns the index of the token containing byte offset, or -1
+func tokenAt(starts []int, offset int) int {
+	for i := len(starts) - 1; i >= 0; i-- {
+		if starts[i] <= offset {
+			return i
+		}
+	}
+	return -1
+}
+
+// candidateScores turns the candidates at a score token into an expected
+// score and the sampled score's share of the probability on valid scores.
+// Candidates that are not scores on the scale are ignored.
+func candidateScores(token openai.LogProb, scale ScoreScale) (logprobScore, bool) {
+	candidates := make(map[float64]float64)
+	addCandidate := func(text string, logprob float64) {
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || value < scale.Min || value > scale.Max {
+			return
+		}
+		if !scale.Decimal && value != math.Trunc(value) {
+			return
+		}
+		candidates[value] += math.Exp(logprob)
+	}
+
+	sampled, err := strconv.ParseFloat(strings.TrimSpace(token.Token), 64)
+	if err != nil {
+		return logprobScore{}, false
+	}
+
+	inTop := false
+	for _, top := range token.TopLogProbs {
+		addCandidate(top.Token, top.LogProb)
+		inTop = inTop || top.Token == token.Token
+	}
+	if !inTop {
+		addCandidate(token.Token, token.LogProb)
+	}
+
+	var total, weighted float64
+	for value, p := range candidates {
+		total += p
+		weighted += value * p
+	}
+	if total == 0 {
+		return logprobScore{}, false
+	}
+
+	return logprobScore{
+		expected:   weighted / total,
+		confidence: candidates[sampled] / total,
+	}, true
+}
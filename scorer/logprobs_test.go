@@ -0,0 +1,160 @@
+// Package scorer_test covers logprob scoring: the request fields, and the
+// expected score and confidence read from the score token's candidates.
+package scorer_test
+
+import (
+	"context"
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+// withCandidates tokenizes content and replaces the candidates at the n-th
+// number token
+func withCandidates(n int, candidates map[string]float64) func(string) *openai.LogProbs {
+	return func(content string) *openai.LogProbs {
+		logprobs := scorertest.FakeLogprobs(content)
+		seen := 0
+		for i, token := range logprobs.Content {
+			if token.Token[0] < '0' || token.Token[0] > '9' {
+				continue
+			}
+			if seen == n {
+				logprobs.Content[i].TopLogProbs = nil
+				for text, p := range candidates {
+					logprobs.Content[i].TopLogProbs = append(logprobs.Content[i].TopLogProbs,
+						openai.TopLogProbs{Token: text, LogProb: math.Log(p)})
+				}
+			}
+			seen++
+		}
+		return logprobs
+	}
+}
+
+var _ = Describe("Logprob scoring", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "a", Content: "Jazz trio at the Blue Note, Friday 8pm"},
+			{ID: "b", Content: "Anyone know a good plumber?"},
+		}
+	})
+
+	It("should request logprobs only when asked", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake, Model: "gpt-4o"})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = s.ScoreTexts(ctx, items, scorer.WithLogprobs())
+		Expect(err).ToNot(HaveOccurred())
+
+		requests := fake.Requests()
+		Expect(requests[0].LogProbs).To(BeFalse())
+		Expect(requests[1].LogProbs).To(BeTrue())
+		Expect(requests[1].TopLogProbs).To(Equal(5))
+	})
+
+	It("should not request logprobs from reasoning models", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake, Model: "o3-mini"}.WithLogprobs())
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()[0].LogProbs).To(BeFalse())
+		Expect(results[0].Confidence).To(BeZero())
+	})
+
+	It("should compute the expected score and confidence from score candidates", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[{"item_id":"a","score":4,"reason":"rated 3 or \"score\": 5"},{"item_id":"b","score":2,"reason":"weak"}]}`
+			},
+			// Number token 1 is item a's score, after the version. Candidate 9 is off
+			// the scale and "x" is not a number, so both are ignored.
+			logprobs: withCandidates(1, map[string]float64{"4": 0.54, "5": 0.27, "3": 0.09, "9": 0.05, "x": 0.05}),
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client}.WithScale(scorer.LikertScale).WithLogprobs())
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(results[0].Score).To(Equal(4))
+		Expect(results[0].ExpectedScore).To(BeNumerically("~", 4.2, 1e-9))
+		Expect(results[0].Confidence).To(BeNumerically("~", 0.6, 1e-9))
+
+		// Item b's token carries only the sampled score
+		Expect(results[1].ExpectedScore).To(Equal(2.0))
+		Expect(results[1].Confidence).To(Equal(1.0))
+	})
+
+	It("should leave scores spanning several tokens without logprob results", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[{"item_id":"a","score":85,"reason":"good"}]}`
+			},
+			logprobs: func(content string) *openai.LogProbs {
+				logprobs := scorertest.FakeLogprobs(content)
+				for i, token := range logprobs.Content {
+					if token.Token == "85" {
+						split := []openai.LogProb{{Token: "8"}, {Token: "5"}}
+						logprobs.Content = append(logprobs.Content[:i], append(split, logprobs.Content[i+1:]...)...)
+						break
+					}
+				}
+				return logprobs
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client}.WithLogprobs())
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items[:1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(85))
+		Expect(results[0].ExpectedScore).To(BeZero())
+		Expect(results[0].Confidence).To(BeZero())
+	})
+
+	It("should expose logprob results through the stub server", func() {
+		server := scorertest.NewServer(scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"a": 80, "b": 20})))
+		defer server.Close()
+
+		s, err := scorer.NewScorer(scorer.Config{
+			APIKey:     "test-key",
+			BaseURL:    server.BaseURL(),
+			HTTPClient: server.Client(),
+		}.WithLogprobs())
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].ExpectedScore).To(Equal(80.0))
+		Expect(results[0].Confidence).To(Equal(1.0))
+		Expect(results[1].ExpectedScore).To(Equal(20.0))
+	})
+
+	It("should skip logprobs for rubric scoring", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithLogprobs())
+		Expect(err).ToNot(HaveOccurred())
+
+		rubric := scorer.Rubric{Dimensions: []scorer.Dimension{{Name: "relevance"}}}
+		_, err = s.ScoreTexts(ctx, items, scorer.WithRubric(rubric))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()[0].LogProbs).To(BeFalse())
+	})
+})
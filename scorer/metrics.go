@@ -3,7 +3,11 @@
 package scorer
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -74,6 +78,63 @@ var (
 		[]string{"name"},
 	)
 
+	// Fallback metrics show how often traffic moves off the primary backend
+	fallbacksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "text_scorer_fallbacks_total",
+			Help: "Total number of requests moved to a fallback backend",
+		},
+		[]string{"from", "to"},
+	)
+
+	// Ensemble metrics count items whose samples disagreed enough to need review
+	ensembleReviews = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "text_scorer_ensemble_reviews_total",
+			Help: "Total number of items flagged for review by ensemble disagreement",
+		},
+	)
+
+	evidenceQuotes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "text_scorer_evidence_quotes_total",
+			Help: "Total number of evidence quotes by whether they were found in the item content",
+		},
+		[]string{"result"},
+	)
+
+	// Response reconciliation metrics count item IDs the model got wrong and the follow-up requests sent for them
+	responseIDIssues = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "text_scorer_response_id_issues_total",
+			Help: "Total number of response item IDs that were missing, duplicated or unknown",
+		},
+		[]string{"kind"},
+	)
+
+	reasksTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "text_scorer_reasks_total",
+			Help: "Total number of follow-up requests for items missing from a response",
+		},
+	)
+
+	jsonRecoveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "text_scorer_json_recoveries_total",
+			Help: "Total number of malformed responses recovered by lenient repair or by asking the model to correct them",
+		},
+		[]string{"kind"},
+	)
+
+	batchSplits = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "text_scorer_batch_splits_total",
+			Help: "Total number of batches split in half after a truncated or refused response",
+		},
+		[]string{"cause"},
+	)
+
 	// Retry mechanism metrics track system robustness under transient failures
 	retryAttempts = promauto.NewHistogram(
 		prometheus.HistogramOpts{
@@ -107,17 +168,13 @@ var (
 			Name: "text_scorer_api_tokens_used_total",
 			Help: "Total number of tokens used in API calls",
 		},
-		[]string{"type"}, // prompt, completion, total
+		[]string{"type"}, // prompt, completion, total, reasoning
 	)
 
-	// Score distribution analysis provides insights into scoring patterns and quality
-	scoreDistribution = promauto.NewHistogram(
-		prometheus.HistogramOpts{
-			Name:    "text_scorer_score_distribution",
-			Help:    "Distribution of scores (0-100)",
-			Buckets: []float64{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
-		},
-	)
+	// Score distribution analysis provides insights into scoring patterns and quality.
+	// Buckets follow the score scale, so each scale gets its own histogram; this is
+	// the default 0-100 one and others are registered on first use.
+	scoreDistribution = promauto.NewHistogram(scoreDistributionOpts(DefaultScale))
 
 	// Concurrency metrics monitor system load and resource utilization
 	concurrentRequests = promauto.NewGauge(
@@ -135,17 +192,75 @@ var (
 	)
 )
 
+// scoreDistributions caches the score histogram for each scale by its label
+var (
+	scoreDistributionsMu sync.Mutex
+	scoreDistributions   = map[string]prometheus.Histogram{DefaultScale.String(): scoreDistribution}
+)
+
+// scoreDistributionOpts describes the score histogram for a scale. The default
+// scale keeps the unlabelled text_scorer_score_distribution series dashboards
+// already use. Other scales go to text_scorer_scaled_score_distribution,
+// labelled by scale, since series sharing a name must share label names.
+func scoreDistributionOpts(scale ScoreScale) prometheus.HistogramOpts {
+	if scale.isDefault() {
+		return prometheus.HistogramOpts{
+			Name:    "text_scorer_score_distribution",
+			Help:    "Distribution of scores (0-100)",
+			Buckets: scale.buckets(),
+		}
+	}
+	return prometheus.HistogramOpts{
+		Name:        "text_scorer_scaled_score_distribution",
+		Help:        "Distribution of scores on non-default scales",
+		ConstLabels: prometheus.Labels{"scale": scale.String()},
+		Buckets:     scale.buckets(),
+	}
+}
+
+// scoreHistogram returns the score histogram for a scale, registering it on first use
+func scoreHistogram(scale ScoreScale) prometheus.Histogram {
+	scoreDistributionsMu.Lock()
+	defer scoreDistributionsMu.Unlock()
+
+	label := scale.String()
+	if histogram, ok := scoreDistributions[label]; ok {
+		return histogram
+	}
+
+	histogram := prometheus.NewHistogram(scoreDistributionOpts(scale))
+	if err := prometheus.Register(histogram); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) {
+			// Observations on the unregistered histogram go nowhere, and
+			// registration is tried again on next use
+			slog.Warn("Failed to register score histogram", "scale", label, "error", err)
+			return histogram
+		}
+		histogram = already.ExistingCollector.(prometheus.Histogram)
+	}
+	scoreDistributions[label] = histogram
+	return histogram
+}
+
 // MetricsRecorder provides methods to record metrics with optional enablement control.
 // When disabled, all recording operations become no-ops for zero performance impact.
 // This design enables metrics collection to be toggled without code changes.
 type MetricsRecorder struct {
 	enabled bool
+	scale   ScoreScale
 }
 
 // NewMetricsRecorder creates a new metrics recorder with the specified enablement state.
 // Production systems typically enable metrics, while test environments may disable them.
 func NewMetricsRecorder(enabled bool) *MetricsRecorder {
-	return &MetricsRecorder{enabled: enabled}
+	return &MetricsRecorder{enabled: enabled, scale: DefaultScale}
+}
+
+// WithScale returns a copy of the recorder that records scores into the
+// histogram for the given scale, with buckets that follow it
+func (m *MetricsRecorder) WithScale(scale ScoreScale) *MetricsRecorder {
+	return &MetricsRecorder{enabled: m.enabled, scale: scale}
 }
 
 // RecordRequest records a request metric
@@ -164,6 +279,18 @@ func (m *MetricsRecorder) RecordRequestDuration(seconds float64, model string) {
 	requestDuration.WithLabelValues(model).Observe(seconds)
 }
 
+// recordOutcome records a request's duration and status, and the error type
+// when it failed
+func (m *MetricsRecorder) recordOutcome(model string, start time.Time, err error) {
+	m.RecordRequestDuration(time.Since(start).Seconds(), model)
+	if err != nil {
+		m.RecordRequest("error", model)
+		m.RecordError(classifyError(err))
+		return
+	}
+	m.RecordRequest("success", model)
+}
+
 // RecordBatchSize records the size of a batch
 func (m *MetricsRecorder) RecordBatchSize(size int) {
 	if !m.enabled {
@@ -180,6 +307,20 @@ func (m *MetricsRecorder) RecordItemsScored(count int) {
 	itemsScored.Add(float64(count))
 }
 
+// recordScores records the count and distribution of scored items, leaving
+// out items the model never scored
+func (m *MetricsRecorder) recordScores(results []ScoredItem) {
+	scored := 0
+	for _, result := range results {
+		if result.Missing {
+			continue
+		}
+		scored++
+		m.RecordScoreValue(result.scoreValue())
+	}
+	m.RecordItemsScored(scored)
+}
+
 // RecordError records an error
 func (m *MetricsRecorder) RecordError(errorType string) {
 	if !m.enabled {
@@ -206,6 +347,67 @@ func (m *MetricsRecorder) RecordCircuitBreakerTrip(name string) {
 	circuitBreakerTrips.WithLabelValues(name).Inc()
 }
 
+// RecordFallback records a request moving from one backend to the next in a fallback chain
+func (m *MetricsRecorder) RecordFallback(from, to string) {
+	if !m.enabled {
+		return
+	}
+	fallbacksTotal.WithLabelValues(from, to).Inc()
+}
+
+// RecordEnsembleReview records an item flagged for review because its ensemble samples disagreed
+func (m *MetricsRecorder) RecordEnsembleReview() {
+	if !m.enabled {
+		return
+	}
+	ensembleReviews.Inc()
+}
+
+// RecordEvidenceQuote records an evidence quote and whether it was found in the item content
+func (m *MetricsRecorder) RecordEvidenceQuote(verified bool) {
+	if !m.enabled {
+		return
+	}
+	result := "verified"
+	if !verified {
+		result = "unverified"
+	}
+	evidenceQuotes.WithLabelValues(result).Inc()
+}
+
+// RecordResponseIDIssue records a response item ID that was missing, duplicated or unknown
+func (m *MetricsRecorder) RecordResponseIDIssue(kind string) {
+	if !m.enabled {
+		return
+	}
+	responseIDIssues.WithLabelValues(kind).Inc()
+}
+
+// RecordReask records a follow-up request for items missing from a response
+func (m *MetricsRecorder) RecordReask() {
+	if !m.enabled {
+		return
+	}
+	reasksTotal.Inc()
+}
+
+// RecordBatchSplit records a batch split in half after a truncated or refused response
+func (m *MetricsRecorder) RecordBatchSplit(cause string) {
+	if !m.enabled {
+		return
+	}
+	batchSplits.WithLabelValues(cause).Inc()
+}
+
+// RecordJSONRecovery records a malformed response recovered by a repair of
+// the given kind: code_fence, trailing_comma, partial_array or reprompt
+func (m *MetricsRecorder) RecordJSONRecovery(kind string) {
+	if !m.enabled {
+		return
+	}
+	jsonRecoveries.WithLabelValues(kind).Inc()
+}
+
 // RecordRetryAttempt records retry attempts
 func (m *MetricsRecorder) RecordRetryAttempt(attempts int) {
 	if !m.enabled {
@@ -231,7 +433,8 @@ func (m *MetricsRecorder) RecordAPICall(endpoint string, status string, seconds
 }
 
 // RecordTokensUsed records API token consumption for cost tracking and optimization.
-// TokenType should be "prompt", "completion", or "total" to categorize usage patterns.
+// TokenType should be "prompt", "completion", "total", or "reasoning" to categorize usage patterns.
+// Reasoning tokens are a subset of completion tokens, recorded separately for reasoning models.
 func (m *MetricsRecorder) RecordTokensUsed(tokenType string, count int) {
 	if !m.enabled {
 		return
@@ -242,10 +445,20 @@ func (m *MetricsRecorder) RecordTokensUsed(tokenType string, count int) {
 // RecordScore records individual score values to analyze scoring distribution patterns.
 // Score distribution analysis helps identify bias, quality issues, and prompt effectiveness.
 func (m *MetricsRecorder) RecordScore(score int) {
+	m.RecordScoreValue(float64(score))
+}
+
+// RecordScoreValue records a score on the recorder's scale, keeping the
+// fractional part of decimal scores
+func (m *MetricsRecorder) RecordScoreValue(value float64) {
 	if !m.enabled {
 		return
 	}
-	scoreDistribution.Observe(float64(score))
+	if m.scale.isDefault() {
+		scoreDistribution.Observe(value)
+		return
+	}
+	scoreHistogram(m.scale).Observe(value)
 }
 
 // RecordConcurrentRequests updates concurrent request count
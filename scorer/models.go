@@ -0,0 +1,157 @@
+package scorer
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ModelCapabilities describes what a model supports and what it costs.
+// The request builder uses it to pick the output mode and generation
+// parameters for each model.
+type ModelCapabilities struct {
+	Name                 string  // Model name as sent to the API
+	StructuredOutputs    bool    // Supports strict json_schema response formats
+	JSONMode             bool    // Supports json_object response formats
+	ContextWindow        int     // Maximum input plus output tokens
+	MaxOutputTokens      int     // Maximum tokens generated per completion
+	CharsPerToken        float64 // Average characters per token, for estimating batch sizes (0 = DefaultCharsPerToken)
+	SupportsTemperature  bool    // Accepts a custom sampling temperature
+	Reasoning            bool    // Reasoning model: takes max_completion_tokens and reasoning_effort
+	NoSystemRole         bool    // Rejects system messages; the system prompt is folded into the user message
+	InputCostPerMillion  float64 // USD per million prompt tokens
+	OutputCostPerMillion float64 // USD per million completion tokens
+}
+
+// OutputMode returns the best structured output mode the model supports
+func (m ModelCapabilities) OutputMode() OutputMode {
+	switch {
+	case m.StructuredOutputs:
+		return OutputModeJSONSchema
+	case m.JSONMode:
+		return OutputModeJSONObject
+	default:
+		return OutputModeText
+	}
+}
+
+// Cost returns the USD cost of a completion with the given token usage
+func (m ModelCapabilities) Cost(promptTokens, completionTokens int) float64 {
+	return float64(promptTokens)*m.InputCostPerMillion/1_000_000 +
+		float64(completionTokens)*m.OutputCostPerMillion/1_000_000
+}
+
+// modelCatalog holds the known models, keyed by name
+var modelCatalog = struct {
+	sync.RWMutex
+	models map[string]ModelCapabilities
+}{
+	models: make(map[string]ModelCapabilities),
+}
+
+// builtinModels lists the models the library knows about out of the box.
+// Prices are list prices in USD per million tokens at the time of writing.
+// Characters per token are for English text: 4.2 for the o200k_base
+// tokenizer used by gpt-4o and later, 4.0 for cl100k_base.
+var builtinModels = []ModelCapabilities{
+	{Name: openai.GPT4o, StructuredOutputs: true, JSONMode: true, ContextWindow: 128_000, MaxOutputTokens: 16_384, CharsPerToken: 4.2,
+		SupportsTemperature: true, InputCostPerMillion: 2.50, OutputCostPerMillion: 10.00},
+	// The first gpt-4o snapshot predates structured outputs
+	{Name: openai.GPT4o20240513, JSONMode: true, ContextWindow: 128_000, MaxOutputTokens: 4_096, CharsPerToken: 4.2,
+		SupportsTemperature: true, InputCostPerMillion: 5.00, OutputCostPerMillion: 15.00},
+	{Name: openai.GPT4oMini, StructuredOutputs: true, JSONMode: true, ContextWindow: 128_000, MaxOutputTokens: 16_384, CharsPerToken: 4.2,
+		SupportsTemperature: true, InputCostPerMillion: 0.15, OutputCostPerMillion: 0.60},
+	{Name: "gpt-4.1", StructuredOutputs: true, JSONMode: true, ContextWindow: 1_047_576, MaxOutputTokens: 32_768, CharsPerToken: 4.2,
+		SupportsTemperature: true, InputCostPerMillion: 2.00, OutputCostPerMillion: 8.00},
+	{Name: "gpt-4.1-mini", StructuredOutputs: true, JSONMode: true, ContextWindow: 1_047_576, MaxOutputTokens: 32_768, CharsPerToken: 4.2,
+		SupportsTemperature: true, InputCostPerMillion: 0.40, OutputCostPerMillion: 1.60},
+	{Name: "gpt-4.1-nano", StructuredOutputs: true, JSONMode: true, ContextWindow: 1_047_576, MaxOutputTokens: 32_768, CharsPerToken: 4.2,
+		SupportsTemperature: true, InputCostPerMillion: 0.10, OutputCostPerMillion: 0.40},
+	{Name: openai.O1, StructuredOutputs: true, JSONMode: true, ContextWindow: 200_000, MaxOutputTokens: 100_000, CharsPerToken: 4.2,
+		Reasoning: true, NoSystemRole: true, InputCostPerMillion: 15.00, OutputCostPerMillion: 60.00},
+	{Name: openai.O1Mini, ContextWindow: 128_000, MaxOutputTokens: 65_536, CharsPerToken: 4.2,
+		Reasoning: true, NoSystemRole: true, InputCostPerMillion: 1.10, OutputCostPerMillion: 4.40},
+	{Name: openai.O1Preview, ContextWindow: 128_000, MaxOutputTokens: 32_768, CharsPerToken: 4.2,
+		Reasoning: true, NoSystemRole: true, InputCostPerMillion: 15.00, OutputCostPerMillion: 60.00},
+	{Name: openai.O3Mini, StructuredOutputs: true, JSONMode: true, ContextWindow: 200_000, MaxOutputTokens: 100_000, CharsPerToken: 4.2,
+		Reasoning: true, InputCostPerMillion: 1.10, OutputCostPerMillion: 4.40},
+	{Name: "o3", StructuredOutputs: true, JSONMode: true, ContextWindow: 200_000, MaxOutputTokens: 100_000, CharsPerToken: 4.2,
+		Reasoning: true, InputCostPerMillion: 2.00, OutputCostPerMillion: 8.00},
+	{Name: "o4-mini", StructuredOutputs: true, JSONMode: true, ContextWindow: 200_000, MaxOutputTokens: 100_000, CharsPerToken: 4.2,
+		Reasoning: true, InputCostPerMillion: 1.10, OutputCostPerMillion: 4.40},
+	{Name: openai.GPT4Turbo, JSONMode: true, ContextWindow: 128_000, MaxOutputTokens: 4_096, CharsPerToken: 4.0,
+		SupportsTemperature: true, InputCostPerMillion: 10.00, OutputCostPerMillion: 30.00},
+	{Name: openai.GPT4, ContextWindow: 8_192, MaxOutputTokens: 8_192, CharsPerToken: 4.0,
+		SupportsTemperature: true, InputCostPerMillion: 30.00, OutputCostPerMillion: 60.00},
+	{Name: openai.GPT432K, ContextWindow: 32_768, MaxOutputTokens: 8_192, CharsPerToken: 4.0,
+		SupportsTemperature: true, InputCostPerMillion: 60.00, OutputCostPerMillion: 120.00},
+	{Name: openai.GPT3Dot5Turbo, JSONMode: true, ContextWindow: 16_385, MaxOutputTokens: 4_096, CharsPerToken: 4.0,
+		SupportsTemperature: true, InputCostPerMillion: 0.50, OutputCostPerMillion: 1.50},
+	{Name: openai.GPT3Dot5Turbo16K, ContextWindow: 16_385, MaxOutputTokens: 4_096, CharsPerToken: 4.0,
+		SupportsTemperature: true, InputCostPerMillion: 3.00, OutputCostPerMillion: 4.00},
+}
+
+func init() {
+	for _, model := range builtinModels {
+		modelCatalog.models[model.Name] = model
+	}
+}
+
+// RegisterModel adds a model to the capability catalog or replaces an existing
+// entry. Registered models pass config validation and get the output mode and
+// parameters their capabilities call for.
+func RegisterModel(capabilities ModelCapabilities) error {
+	if strings.TrimSpace(capabilities.Name) == "" {
+		return errors.New("model name is required")
+	}
+	if capabilities.ContextWindow < 0 || capabilities.MaxOutputTokens < 0 {
+		return errors.New("token limits must be non-negative")
+	}
+	if capabilities.CharsPerToken < 0 {
+		return errors.New("CharsPerToken must be non-negative")
+	}
+
+	modelCatalog.Lock()
+	defer modelCatalog.Unlock()
+	modelCatalog.models[capabilities.Name] = capabilities
+	return nil
+}
+
+// LookupModel returns the capabilities of a model. Dated snapshots such as
+// gpt-4o-2024-08-06 resolve to the longest registered name they extend, unless
+// the snapshot is registered in its own right.
+func LookupModel(name string) (ModelCapabilities, bool) {
+	modelCatalog.RLock()
+	defer modelCatalog.RUnlock()
+
+	if caps, ok := modelCatalog.models[name]; ok {
+		return caps, true
+	}
+
+	var best string
+	for registered := range modelCatalog.models {
+		if strings.HasPrefix(name, registered+"-") && len(registered) > len(best) {
+			best = registered
+		}
+	}
+	if best == "" {
+		return ModelCapabilities{}, false
+	}
+	return modelCatalog.models[best], true
+}
+
+// RegisteredModels returns the names of all models in the catalog, sorted
+func RegisteredModels() []string {
+	modelCatalog.RLock()
+	defer modelCatalog.RUnlock()
+
+	names := make([]string, 0, len(modelCatalog.models))
+	for name := range modelCatalog.models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
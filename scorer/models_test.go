@@ -0,0 +1,128 @@
+// Package scorer_test covers the model capability catalog: lookups, snapshot
+// resolution, custom registration and how capabilities drive the response
+// format chosen for each request.
+package scorer_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+)
+
+var _ = Describe("Model catalog", func() {
+	Describe("LookupModel", func() {
+		It("should know the built-in OpenAI models", func() {
+			caps, ok := scorer.LookupModel(openai.GPT4oMini)
+			Expect(ok).To(BeTrue())
+			Expect(caps.StructuredOutputs).To(BeTrue())
+			Expect(caps.ContextWindow).To(Equal(128_000))
+			Expect(caps.OutputMode()).To(Equal(scorer.OutputModeJSONSchema))
+		})
+
+		It("should resolve dated snapshots to the longest matching model", func() {
+			caps, ok := scorer.LookupModel("gpt-4o-mini-2024-07-18")
+			Expect(ok).To(BeTrue())
+			Expect(caps.Name).To(Equal(openai.GPT4oMini))
+		})
+
+		It("should prefer an explicitly registered snapshot", func() {
+			caps, ok := scorer.LookupModel(openai.GPT4o20240513)
+			Expect(ok).To(BeTrue())
+			Expect(caps.StructuredOutputs).To(BeFalse())
+			Expect(caps.OutputMode()).To(Equal(scorer.OutputModeJSONObject))
+		})
+
+		It("should not match unrelated names", func() {
+			_, ok := scorer.LookupModel("gpt-4ox")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should describe models without JSON support as text mode", func() {
+			caps, ok := scorer.LookupModel(openai.GPT4)
+			Expect(ok).To(BeTrue())
+			Expect(caps.OutputMode()).To(Equal(scorer.OutputModeText))
+		})
+	})
+
+	Describe("RegisterModel", func() {
+		It("should make custom models valid in config", func() {
+			cfg := scorer.NewDefaultConfig("test-key").WithModel("acme-scorer-7b")
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("unsupported model")))
+
+			Expect(scorer.RegisterModel(scorer.ModelCapabilities{
+				Name:          "acme-scorer-7b",
+				JSONMode:      true,
+				ContextWindow: 32_000,
+			})).To(Succeed())
+
+			Expect(cfg.Validate()).To(Succeed())
+			Expect(scorer.RegisteredModels()).To(ContainElement("acme-scorer-7b"))
+		})
+
+		It("should reject a model without a name", func() {
+			Expect(scorer.RegisterModel(scorer.ModelCapabilities{})).To(MatchError(ContainSubstring("name is required")))
+		})
+	})
+
+	Describe("Cost", func() {
+		It("should price usage from per-million rates", func() {
+			caps := scorer.ModelCapabilities{InputCostPerMillion: 2.0, OutputCostPerMillion: 8.0}
+			Expect(caps.Cost(500_000, 250_000)).To(BeNumerically("~", 3.0, 1e-9))
+		})
+	})
+
+	Describe("response format negotiation", func() {
+		var (
+			client *mockScoringClient
+			items  []scorer.TextItem
+		)
+
+		BeforeEach(func() {
+			client = &mockScoringClient{
+				respond: func(req openai.ChatCompletionRequest) string {
+					return `{"version":"1.0","scores":[{"item_id":"1","score":50,"reason":"maybe"}]}`
+				},
+			}
+			items = []scorer.TextItem{{ID: "1", Content: "Pub quiz on Tuesdays"}}
+		})
+
+		DescribeTable("should pick the output mode from the model's capabilities",
+			func(model string, expectedType openai.ChatCompletionResponseFormatType, hasFormat bool) {
+				s, err := scorer.NewScorer(scorer.Config{Client: client, Model: model})
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = s.ScoreTexts(context.Background(), items)
+				Expect(err).ToNot(HaveOccurred())
+
+				req := client.requests[0]
+				if !hasFormat {
+					Expect(req.ResponseFormat).To(BeNil())
+					Expect(req.Messages[0].Content).To(ContainSubstring("matching this JSON schema"))
+					return
+				}
+				Expect(req.ResponseFormat).ToNot(BeNil())
+				Expect(req.ResponseFormat.Type).To(Equal(expectedType))
+			},
+			Entry("structured outputs", openai.GPT4oMini, openai.ChatCompletionResponseFormatTypeJSONSchema, true),
+			Entry("JSON mode only", openai.GPT3Dot5Turbo, openai.ChatCompletionResponseFormatTypeJSONObject, true),
+			Entry("no JSON support", openai.GPT4, openai.ChatCompletionResponseFormatType(""), false),
+		)
+
+		It("should let an explicit output mode override the catalog", func() {
+			s, err := scorer.NewScorer(scorer.Config{
+				Client:     client,
+				Model:      openai.GPT4,
+				OutputMode: scorer.OutputModeJSONSchema,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s.ScoreTexts(context.Background(), items)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.requests[0].ResponseFormat.Type).To(Equal(openai.ChatCompletionResponseFormatTypeJSONSchema))
+		})
+	})
+})
@@ -0,0 +1,288 @@
+package scorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Partial scoring errors, wrapped in ItemFailure.Err
+var (
+	ErrItemMissing = errors.New("item missing from response")
+	ErrBatchFailed = errors.New("batch failed")
+)
+
+// BatchError reports a batch that failed. Every item in the batch is returned
+// Missing with the BatchError as its Err, and the scorer's error joins the
+// BatchErrors of all failed batches.
+type BatchError struct {
+	Batch int   // Index of the failed batch
+	Err   error // Why the batch failed, such as an API error or a cancelled context
+}
+
+// Error implements the error interface
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%v (batch %d): %v", ErrBatchFailed, e.Batch, e.Err)
+}
+
+// Unwrap returns ErrBatchFailed and the batch's error, so errors.Is matches both
+func (e *BatchError) Unwrap() []error {
+	return []error{ErrBatchFailed, e.Err}
+}
+
+// batchFailedReason is the reason given to items whose batch failed
+const batchFailedReason = "Not scored: batch failed"
+
+// ItemFailure records why an item was not scored
+type ItemFailure struct {
+	Item  TextItem // Original text item
+	Batch int      // Index of the batch the item was sent in; -1 when it failed validation and was never sent
+	Err   error    // ErrItemMissing, a *RefusalError, a *BatchError, or the validation error
+}
+
+// ScoreResult is the outcome of a partial scoring run: every item that was
+// scored, and why each remaining item was not
+type ScoreResult struct {
+	Scored []ScoredItem  // Scored items, in input order
+	Failed []ItemFailure // Items that were not scored, in input order
+}
+
+// PartialScorer scores items without discarding successful batches when
+// others fail
+type PartialScorer interface {
+	// ScoreTextsPartial scores every item it can. The error is reserved for
+	// problems with the request as a whole, such as nil items or invalid
+	// options; per-item and per-batch failures, including batches cut short
+	// or never started because the context was cancelled, are reported in
+	// the result.
+	ScoreTextsPartial(ctx context.Context, items []TextItem, opts ...ScoringOption) (*ScoreResult, error)
+}
+
+// Err summarises the failures, or returns nil when every item was scored
+func (r *ScoreResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(r.Failed))
+	for i, failure := range r.Failed {
+		errs[i] = fmt.Errorf("item %s: %w", failure.Item.ID, failure.Err)
+	}
+	return fmt.Errorf("%d of %d items not scored: %w",
+		len(r.Failed), len(r.Scored)+len(r.Failed), errors.Join(errs...))
+}
+
+// ScoreTextsPartial scores items through any Scorer, keeping the results of
+// batches that succeed. Scorers that implement PartialScorer are used
+// directly. Others are called once with every item and split into batches as
+// they are configured to; a scorer that returns only an error, such as for an
+// invalid item, fails the request, and items are reported in batch 0 unless
+// their Err is a *BatchError.
+func ScoreTextsPartial(ctx context.Context, s Scorer, items []TextItem, opts ...ScoringOption) (*ScoreResult, error) {
+	if partial, ok := s.(PartialScorer); ok {
+		return partial.ScoreTextsPartial(ctx, items, opts...)
+	}
+	if items == nil {
+		return nil, errors.New("items cannot be nil")
+	}
+
+	results, err := s.ScoreTextsWithOptions(ctx, items, opts...)
+	if results == nil && len(items) > 0 {
+		return nil, err
+	}
+	if len(results) != len(items) {
+		return nil, fmt.Errorf("scorer returned %d results for %d items", len(results), len(items))
+	}
+
+	result := newScoreResult(results, make([]*ItemFailure, len(items)))
+	logPartial(result)
+	return result, nil
+}
+
+// ScoreTextsPartial implements PartialScorer. Invalid items are reported
+// instead of failing the request, and the rest are scored as ScoreTexts
+// scores them, with batches running concurrently up to Config.MaxConcurrent
+// without cancelling each other.
+func (s *scorer) ScoreTextsPartial(ctx context.Context, items []TextItem, opts ...ScoringOption) (*ScoreResult, error) {
+	return scorePartial(ctx, s.config, items, func(ctx context.Context, valid []TextItem) ([]ScoredItem, error) {
+		return s.ScoreTextsWithOptions(ctx, valid, opts...)
+	})
+}
+
+// ScoreTextsPartial implements PartialScorer. Scoring goes through the full
+// retry, circuit breaker, fallback and metrics stack, and the resilience
+// layers re-send only the batches that failed.
+func (s *IntegratedScorer) ScoreTextsPartial(ctx context.Context, items []TextItem, opts ...ScoringOption) (*ScoreResult, error) {
+	return scorePartial(ctx, s.config, items, func(ctx context.Context, valid []TextItem) ([]ScoredItem, error) {
+		return s.ScoreTextsWithOptions(ctx, valid, opts...)
+	})
+}
+
+// scorePartial validates items one by one against cfg, scores the valid ones
+// in a single call to score, and sorts every item into scored or failed. Only
+// an error without results, such as invalid options, fails the request.
+func scorePartial(ctx context.Context, cfg Config, items []TextItem, score func(ctx context.Context, valid []TextItem) ([]ScoredItem, error)) (*ScoreResult, error) {
+	if items == nil {
+		return nil, errors.New("items cannot be nil")
+	}
+
+	// Per-input slots keep scored and failed items in input order
+	failed := make([]*ItemFailure, len(items))
+
+	var valid []TextItem
+	for i, item := range items {
+		if err := cfg.validateItem(i, item); err != nil {
+			slog.Warn("Skipping invalid item", "item_id", item.ID, "index", i, "error", err)
+			failed[i] = &ItemFailure{Item: item, Batch: -1, Err: err}
+			continue
+		}
+		valid = append(valid, item)
+	}
+
+	results := make([]ScoredItem, len(items))
+	if len(valid) > 0 {
+		scored, err := score(ctx, valid)
+		if scored == nil {
+			return nil, err
+		}
+		if len(scored) != len(valid) {
+			return nil, fmt.Errorf("scorer returned %d results for %d items", len(scored), len(valid))
+		}
+
+		next := 0
+		for i := range items {
+			if failed[i] == nil {
+				results[i] = scored[next]
+				next++
+			}
+		}
+	}
+
+	result := newScoreResult(results, failed)
+	logPartial(result)
+	return result, nil
+}
+
+// newScoreResult sorts results into scored and failed items in input order.
+// Slots already set in failed take precedence over the matching result.
+func newScoreResult(results []ScoredItem, failed []*ItemFailure) *ScoreResult {
+	result := &ScoreResult{Scored: []ScoredItem{}, Failed: []ItemFailure{}}
+	for i, item := range results {
+		switch {
+		case failed[i] != nil:
+			result.Failed = append(result.Failed, *failed[i])
+		case item.Missing:
+			itemErr := item.Err
+			if itemErr == nil {
+				itemErr = ErrItemMissing
+			}
+			batch := item.batch
+			var batchErr *BatchError
+			if errors.As(itemErr, &batchErr) {
+				batch = batchErr.Batch
+			}
+			result.Failed = append(result.Failed, ItemFailure{Item: item.Item, Batch: batch, Err: itemErr})
+		default:
+			result.Scored = append(result.Scored, item)
+		}
+	}
+	return result
+}
+
+// logPartial logs the outcome of a partial scoring run
+func logPartial(result *ScoreResult) {
+	slog.Info("Partial scoring complete",
+		"total_items", len(result.Scored)+len(result.Failed),
+		"scored", len(result.Scored),
+		"failed", len(result.Failed))
+}
+
+// failBatch returns the results for the items of a batch that failed
+func failBatch(batch []TextItem, index int, err error) []ScoredItem {
+	batchErr := &BatchError{Batch: index, Err: err}
+	results := make([]ScoredItem, len(batch))
+	for i, item := range batch {
+		results[i] = ScoredItem{Item: item, Reason: batchFailedReason, Missing: true, Err: batchErr, batch: index}
+	}
+	return results
+}
+
+// collectBatches flattens the per-batch results of runAll in batch order.
+// The items of a batch that failed get the result from failed with a
+// *BatchError, and the error joins those batch errors.
+func collectBatches[R any](batches [][]TextItem, outcomes [][]R, errs []error, failed func(TextItem, *BatchError) R) ([]R, error) {
+	var results []R
+	var batchErrs []error
+	for i, batch := range batches {
+		if errs[i] == nil {
+			results = append(results, outcomes[i]...)
+			continue
+		}
+
+		slog.Warn("Batch failed, keeping other batches",
+			"batch", i,
+			"items", len(batch),
+			"error", errs[i])
+		batchErr := &BatchError{Batch: i, Err: errs[i]}
+		batchErrs = append(batchErrs, batchErr)
+		for _, item := range batch {
+			results = append(results, failed(item, batchErr))
+		}
+	}
+	return results, errors.Join(batchErrs...)
+}
+
+// failedBatchItems returns the items whose batch failed with an error that
+// resend accepts, and their positions in results. Each batch is judged by its
+// own error, so a batch that failed for good is not sent again alongside one
+// that can be.
+func failedBatchItems(results []ScoredItem, resend func(err error) bool) ([]TextItem, []int) {
+	var items []TextItem
+	var positions []int
+	for i, result := range results {
+		var batchErr *BatchError
+		if errors.As(result.Err, &batchErr) && resend(batchErr.Err) {
+			items = append(items, result.Item)
+			positions = append(positions, i)
+		}
+	}
+	return items, positions
+}
+
+// mergeRescored puts the results of scoring the items at positions again into
+// results. Items keep the batch index of their first attempt, so one that
+// fails again reports its original batch with the new error. When rescoring
+// returned no results at all, every item fails again with err.
+func mergeRescored(results []ScoredItem, positions []int, rescored []ScoredItem, err error) {
+	for j, position := range positions {
+		batch := results[position].batch
+		if rescored == nil {
+			results[position] = failBatch([]TextItem{results[position].Item}, batch, err)[0]
+			continue
+		}
+
+		result := rescored[j]
+		var batchErr *BatchError
+		if errors.As(result.Err, &batchErr) {
+			result.Err = &BatchError{Batch: batch, Err: batchErr.Err}
+		}
+		result.batch = batch
+		results[position] = result
+	}
+}
+
+// batchErrors joins the distinct batch errors among results, or returns nil
+// when no batch failed
+func batchErrors(results []ScoredItem) error {
+	var errs []error
+	seen := make(map[int]bool)
+	for _, result := range results {
+		var batchErr *BatchError
+		if errors.As(result.Err, &batchErr) && !seen[batchErr.Batch] {
+			seen[batchErr.Batch] = true
+			errs = append(errs, batchErr)
+		}
+	}
+	return errors.Join(errs...)
+}
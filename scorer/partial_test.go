@@ -0,0 +1,435 @@
+// Package scorer_test covers partial results: keeping successful batches when
+// others fail or the context is cancelled, reporting missing and invalid
+// items, and the summary error.
+package scorer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+// plainScorer hides ScoreTextsPartial so the generic path is exercised
+type plainScorer struct {
+	scorer.Scorer
+}
+
+var _ = Describe("Partial results", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = make([]scorer.TextItem, 20)
+		for i := range items {
+			items[i] = scorer.TextItem{ID: fmt.Sprintf("%d", i+1), Content: fmt.Sprintf("Post %d", i+1)}
+		}
+	})
+
+	It("should keep the scores of batches that succeed", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 400, Message: "bad request"})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := s.(scorer.PartialScorer).ScoreTextsPartial(ctx, items, scorer.WithTokenBudget(1000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(fake.Requests())).To(BeNumerically(">", 1))
+		Expect(result.Failed).ToNot(BeEmpty())
+		Expect(result.Scored).ToNot(BeEmpty())
+		Expect(len(result.Scored) + len(result.Failed)).To(Equal(20))
+
+		// The failed batch is one contiguous run of items
+		failedBatch := result.Failed[0].Batch
+		first := indexOf(items, result.Failed[0].Item.ID)
+		for i, failure := range result.Failed {
+			Expect(failure.Item).To(Equal(items[first+i]))
+			Expect(failure.Batch).To(Equal(failedBatch))
+			Expect(failure.Err).To(MatchError(scorer.ErrBatchFailed))
+			Expect(failure.Err.Error()).To(ContainSubstring("bad request"))
+		}
+
+		previous := -1
+		for _, scored := range result.Scored {
+			index := indexOf(items, scored.Item.ID)
+			Expect(index).To(BeNumerically(">", previous))
+			previous = index
+			Expect(scored.Score).To(Equal(scorertest.DefaultScore))
+		}
+	})
+
+	It("should report items missing from the response instead of scoring them", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{MissingIDs: []string{"2"}})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := s.(scorer.PartialScorer).ScoreTextsPartial(ctx, items[:3])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Scored).To(HaveLen(2))
+		Expect(result.Failed).To(HaveLen(1))
+		Expect(result.Failed[0].Item.ID).To(Equal("2"))
+		Expect(result.Failed[0].Err).To(MatchError(scorer.ErrItemMissing))
+	})
+
+	It("should report invalid items without sending them", func() {
+		items[1].Content = strings.Repeat("x", 20)
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake, MaxContentLength: 10})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := s.(scorer.PartialScorer).ScoreTextsPartial(ctx, items[:3])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Scored).To(HaveLen(2))
+		Expect(result.Failed).To(HaveLen(1))
+		Expect(result.Failed[0].Batch).To(Equal(-1))
+		Expect(result.Failed[0].Err).To(MatchError(scorer.ErrContentTooLong))
+		Expect(fake.Requests()[0].Messages[1].Content).ToNot(ContainSubstring("(ID: 2)"))
+	})
+
+	It("should run batches concurrently without one failure cancelling the rest", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 400})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake, MaxConcurrent: 3})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := s.(scorer.PartialScorer).ScoreTextsPartial(ctx, items, scorer.WithTokenBudget(1000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Failed).ToNot(BeEmpty())
+		Expect(result.Scored).ToNot(BeEmpty())
+		Expect(len(result.Scored) + len(result.Failed)).To(Equal(20))
+	})
+
+	It("should score each batch through the integrated scorer's full stack", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 400})
+		s, err := scorer.NewIntegratedScorer(scorer.Config{Client: fake, BatchTokenBudget: 1000})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := scorer.ScoreTextsPartial(ctx, s, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Failed).ToNot(BeEmpty())
+		Expect(result.Scored).ToNot(BeEmpty())
+		Expect(len(result.Scored) + len(result.Failed)).To(Equal(20))
+	})
+
+	It("should split batches as the scorer is configured to for other scorers", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 400})
+		base, err := scorer.NewScorer(scorer.Config{Client: fake, BatchTokenBudget: 1000})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := scorer.ScoreTextsPartial(ctx, plainScorer{base}, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(BeNumerically(">", 1))
+		Expect(result.Failed).ToNot(BeEmpty())
+		Expect(result.Scored).ToNot(BeEmpty())
+		Expect(len(result.Scored) + len(result.Failed)).To(Equal(20))
+		for _, failure := range result.Failed {
+			Expect(failure.Batch).To(Equal(0))
+			Expect(failure.Err).To(MatchError(scorer.ErrBatchFailed))
+		}
+	})
+
+	It("should fail the request when another scorer returns only an error", func() {
+		items[1].Content = strings.Repeat("x", 20)
+		base, err := scorer.NewScorer(scorer.Config{Client: scorertest.NewFakeClient(), MaxContentLength: 10})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = scorer.ScoreTextsPartial(ctx, plainScorer{base}, items[:3])
+		Expect(err).To(MatchError(scorer.ErrContentTooLong))
+	})
+
+	It("should report batches cut short by cancellation instead of failing", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		s, err := scorer.NewScorer(scorer.Config{Client: scorertest.NewFakeClient()})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := s.(scorer.PartialScorer).ScoreTextsPartial(cancelled, items, scorer.WithTokenBudget(1000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Scored).To(BeEmpty())
+		Expect(result.Failed).To(HaveLen(20))
+		for _, failure := range result.Failed {
+			Expect(failure.Err).To(MatchError(scorer.ErrBatchFailed))
+			Expect(failure.Err).To(MatchError(context.Canceled))
+		}
+		Expect(result.Failed[19].Batch).To(BeNumerically(">", 0))
+	})
+
+	It("should keep the scores of batches that finished before cancellation", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		defer cancel()
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: &cancelAfterClient{OpenAIClient: fake, calls: 1, cancel: cancel}})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := s.(scorer.PartialScorer).ScoreTextsPartial(cancelled, items, scorer.WithTokenBudget(1000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(Equal(1))
+		Expect(result.Scored).ToNot(BeEmpty())
+		Expect(result.Failed).ToNot(BeEmpty())
+		Expect(len(result.Scored) + len(result.Failed)).To(Equal(20))
+		Expect(result.Scored[0].Item.ID).To(Equal("1"))
+		for _, failure := range result.Failed {
+			Expect(failure.Batch).To(BeNumerically(">", 0))
+			Expect(failure.Err).To(MatchError(context.Canceled))
+		}
+	})
+
+	It("should return completed batches alongside the error from ScoreTexts", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 400, Message: "bad request"})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake, MaxConcurrent: 3})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items, scorer.WithTokenBudget(1000))
+		Expect(err).To(MatchError(scorer.ErrBatchFailed))
+		Expect(err).To(MatchError(ContainSubstring("bad request")))
+		Expect(results).To(HaveLen(20))
+
+		missing := 0
+		for i, result := range results {
+			Expect(result.Item).To(Equal(items[i]))
+			if result.Missing {
+				missing++
+				var batchErr *scorer.BatchError
+				Expect(errors.As(result.Err, &batchErr)).To(BeTrue())
+				Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("(batch %d)", batchErr.Batch))))
+				continue
+			}
+			Expect(result.Score).To(Equal(scorertest.DefaultScore))
+		}
+		Expect(missing).To(BeNumerically(">", 0))
+		Expect(missing).To(BeNumerically("<", 20))
+	})
+
+	It("should retry only the batches that failed", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 503})
+		cfg := scorer.Config{Client: fake, BatchTokenBudget: 1000}.WithRetryConfig(&scorer.RetryConfig{
+			MaxAttempts:  2,
+			Strategy:     scorer.RetryStrategyConstant,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     time.Millisecond,
+		})
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		batches := batchCount(ctx, items)
+		Expect(batches).To(BeNumerically(">", 2))
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(20))
+		for i, result := range results {
+			Expect(result.Item).To(Equal(items[i]))
+			Expect(result.Missing).To(BeFalse())
+		}
+		Expect(fake.Calls()).To(Equal(batches + 1))
+	})
+
+	DescribeTable("should judge each failed batch by its own error when retrying",
+		func(faults []scorertest.Fault, retriedBatch, failedBatch int) {
+			fake := scorertest.NewFakeClient()
+			fake.FailNext(faults...)
+			cfg := scorer.Config{Client: fake, BatchTokenBudget: 1000}.WithRetryConfig(&scorer.RetryConfig{
+				MaxAttempts:  3,
+				Strategy:     scorer.RetryStrategyConstant,
+				InitialDelay: time.Millisecond,
+				MaxDelay:     time.Millisecond,
+			})
+			s, err := scorer.NewIntegratedScorer(cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, items)
+			Expect(err).To(MatchError(scorer.ErrBatchFailed))
+			Expect(err).To(MatchError(ContainSubstring("(batch %d)", failedBatch)))
+			Expect(err).ToNot(MatchError(ContainSubstring("(batch %d)", retriedBatch)))
+			Expect(results).To(HaveLen(20))
+
+			failed := 0
+			for _, result := range results {
+				var batchErr *scorer.BatchError
+				if errors.As(result.Err, &batchErr) {
+					Expect(batchErr.Batch).To(Equal(failedBatch))
+					failed++
+					continue
+				}
+				Expect(result.Missing).To(BeFalse())
+			}
+			Expect(failed).To(BeNumerically(">", 0))
+
+			// Only the retryable batch is sent a second time
+			sent := fake.Requests()[len(fake.Requests())-1].Messages[1].Content
+			for _, result := range results {
+				if result.Missing {
+					Expect(sent).ToNot(ContainSubstring(fmt.Sprintf("(ID: %s)", result.Item.ID)))
+				}
+			}
+			Expect(fake.Calls()).To(Equal(batchCount(ctx, items) + 1))
+		},
+		Entry("client error first", []scorertest.Fault{{Status: 400}, {Status: 503}}, 1, 0),
+		Entry("server error first", []scorertest.Fault{{Status: 503}, {Status: 400}}, 0, 1),
+	)
+
+	It("should send only the batches that warrant it to the fallback backend", func() {
+		primary := scorertest.NewFakeClient()
+		primary.FailNext(scorertest.Fault{Status: 400}, scorertest.Fault{Status: 500})
+		secondary := scorertest.NewFakeClient()
+
+		first, err := scorer.NewScorer(scorer.Config{Client: primary, BatchTokenBudget: 1000})
+		Expect(err).ToNot(HaveOccurred())
+		second, err := scorer.NewScorer(scorer.Config{Client: secondary, Model: openai.GPT4o, BatchTokenBudget: 1000})
+		Expect(err).ToNot(HaveOccurred())
+		s, err := scorer.NewFallbackScorer(
+			scorer.Backend{Name: "primary", Scorer: first},
+			scorer.Backend{Name: "secondary", Scorer: second},
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).To(MatchError(ContainSubstring("(batch 0)")))
+		Expect(err).ToNot(MatchError(ContainSubstring("(batch 1)")))
+		Expect(secondary.Calls()).To(Equal(1))
+
+		sent := secondary.Requests()[0].Messages[1].Content
+		for _, result := range results {
+			var batchErr *scorer.BatchError
+			if errors.As(result.Err, &batchErr) {
+				Expect(batchErr.Batch).To(Equal(0))
+				Expect(sent).ToNot(ContainSubstring(fmt.Sprintf("(ID: %s)", result.Item.ID)))
+				continue
+			}
+			Expect(result.Missing).To(BeFalse())
+			if result.Backend == "openai/gpt-4o" {
+				Expect(sent).To(ContainSubstring(fmt.Sprintf("(ID: %s)", result.Item.ID)))
+			}
+		}
+	})
+
+	It("should send only the failed batches to the fallback backend", func() {
+		primary := scorertest.NewFakeClient()
+		primary.FailNext(scorertest.Fault{Status: 500})
+		secondary := scorertest.NewFakeClient()
+
+		first, err := scorer.NewScorer(scorer.Config{Client: primary, BatchTokenBudget: 1000})
+		Expect(err).ToNot(HaveOccurred())
+		second, err := scorer.NewScorer(scorer.Config{Client: secondary, Model: openai.GPT4o, BatchTokenBudget: 1000})
+		Expect(err).ToNot(HaveOccurred())
+		s, err := scorer.NewFallbackScorer(
+			scorer.Backend{Name: "primary", Scorer: first},
+			scorer.Backend{Name: "secondary", Scorer: second},
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(20))
+		Expect(secondary.Calls()).To(Equal(1))
+
+		sent := secondary.Requests()[0].Messages[1].Content
+		for i, result := range results {
+			Expect(result.Item).To(Equal(items[i]))
+			Expect(result.Missing).To(BeFalse())
+			if result.Backend == "openai/gpt-4o" {
+				Expect(sent).To(ContainSubstring(fmt.Sprintf("(ID: %s)", result.Item.ID)))
+			} else {
+				Expect(result.Backend).To(Equal("openai/gpt-4o-mini"))
+				Expect(sent).ToNot(ContainSubstring(fmt.Sprintf("(ID: %s)", result.Item.ID)))
+			}
+		}
+		Expect(results[0].Backend).To(Equal("openai/gpt-4o"))
+		Expect(results[19].Backend).To(Equal("openai/gpt-4o-mini"))
+	})
+
+	It("should return judged batches alongside the error", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 400, Message: "bad request"})
+		judge, err := scorer.NewJudge(scorer.Config{Client: fake, BatchTokenBudget: 1000}, scorer.JudgeCorrectness)
+		Expect(err).ToNot(HaveOccurred())
+
+		judgeItems := make([]scorer.JudgeItem, len(items))
+		for i, item := range items {
+			judgeItems[i] = scorer.JudgeItem{ID: item.ID, Candidate: item.Content, Reference: item.Content}
+		}
+
+		results, err := judge.Judge(ctx, judgeItems)
+		Expect(err).To(MatchError(scorer.ErrBatchFailed))
+		Expect(results).To(HaveLen(20))
+		Expect(results[0].Missing).To(BeTrue())
+		Expect(results[0].Err).To(MatchError(ContainSubstring("bad request")))
+		Expect(results[19].Missing).To(BeFalse())
+	})
+
+	It("should summarise failures in Err", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := s.(scorer.PartialScorer).ScoreTextsPartial(ctx, items[:2])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Err()).ToNot(HaveOccurred())
+
+		result.Failed = []scorer.ItemFailure{{Item: items[2], Batch: 0, Err: scorer.ErrItemMissing}}
+		Expect(result.Err()).To(MatchError(ContainSubstring("1 of 3 items not scored")))
+		Expect(errors.Is(result.Err(), scorer.ErrItemMissing)).To(BeTrue())
+	})
+
+	It("should reject nil items", func() {
+		s, err := scorer.NewScorer(scorer.Config{Client: scorertest.NewFakeClient()})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.(scorer.PartialScorer).ScoreTextsPartial(ctx, nil)
+		Expect(err).To(MatchError("items cannot be nil"))
+	})
+})
+
+// batchCount returns how many batches items make with a 1000 token budget,
+// counted by scoring them with a client that never fails
+func batchCount(ctx context.Context, items []scorer.TextItem) int {
+	fake := scorertest.NewFakeClient()
+	s, err := scorer.NewScorer(scorer.Config{Client: fake, BatchTokenBudget: 1000})
+	Expect(err).ToNot(HaveOccurred())
+	_, err = s.ScoreTexts(ctx, items)
+	Expect(err).ToNot(HaveOccurred())
+	return fake.Calls()
+}
+
+// indexOf returns the position of the item with the given ID
+func indexOf(items []scorer.TextItem, id string) int {
+	for i, item := range items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// cancelAfterClient cancels the context once the wrapped client has answered
+// the given number of calls
+type cancelAfterClient struct {
+	scorer.OpenAIClient
+	calls  int
+	cancel context.CancelFunc
+}
+
+// CreateChatCompletion forwards the request and cancels after the last allowed call
+func (c *cancelAfterClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	resp, err := c.OpenAIClient.CreateChatCompletion(ctx, req)
+	if c.calls--; c.calls == 0 {
+		c.cancel()
+	}
+	return resp, err
+}
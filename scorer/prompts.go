@@ -4,8 +4,6 @@ import (
 	"fmt"
 )
 
-const maxBatchSize = 10
-
 var batchScorePrompt string
 var batchPromptError error
 
@@ -0,0 +1,345 @@
+package scorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+const (
+	// DefaultRankingGroupSize is the number of items compared per call
+	DefaultRankingGroupSize = 4
+	// DefaultRankingRounds is the number of groups each item is compared in
+	DefaultRankingRounds = 3
+
+	// maxRankingGroupSize keeps groups small enough for reliable orderings
+	maxRankingGroupSize = 10
+)
+
+var rankingSystemPrompt string
+var rankingPrompt string
+var rankingPromptError error
+
+func init() {
+	systemBytes, err := promptFS.ReadFile("prompts/ranking_system_prompt.txt")
+	if err != nil {
+		rankingPromptError = fmt.Errorf("failed to load ranking system prompt: %w", err)
+		return
+	}
+	rankingSystemPrompt = string(systemBytes)
+
+	promptBytes, err := promptFS.ReadFile("prompts/ranking_prompt.txt")
+	if err != nil {
+		rankingPromptError = fmt.Errorf("failed to load ranking prompt: %w", err)
+		return
+	}
+	rankingPrompt = string(promptBytes)
+}
+
+// Ranker orders text items by comparing them against each other rather than
+// scoring each one in isolation. Relative judgements are more stable than
+// absolute scores across batches, which makes ranking the better tool for
+// picking the top N items.
+type Ranker interface {
+	// Rank returns every item ordered from best to worst. When some groups
+	// fail, the ranking is fitted on the comparisons from the groups that
+	// succeeded and returned alongside the error.
+	Rank(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]RankedItem, error)
+}
+
+// RankedItem is a text item with its position in a ranking
+type RankedItem struct {
+	Item        TextItem // Original text item
+	Rank        int      // Position in the ranking, starting at 1
+	Strength    float64  // Bradley-Terry log-strength; 0 is average, higher is better
+	Wins        int      // Pairwise comparisons won
+	Comparisons int      // Pairwise comparisons taken part in
+}
+
+// RankingConfig controls how a tournament is scheduled
+type RankingConfig struct {
+	GroupSize int   // Items compared per call; 2 is pure pairwise (default: DefaultRankingGroupSize)
+	Rounds    int   // Groups each item is compared in (default: DefaultRankingRounds)
+	Seed      int64 // Seed for group assignment so tournaments are reproducible
+}
+
+// Internal response types for ranking JSON parsing
+type rankingResponse struct {
+	Ranking []string `json:"ranking"`
+	Reason  string   `json:"reason"`
+}
+
+// ranker runs tournaments through a scorer's client, prompts and output mode handling
+type ranker struct {
+	scorer  *scorer
+	ranking RankingConfig
+}
+
+// NewRanker creates a ranker. Items are shuffled into small groups over
+// several rounds, the model orders each group, and the pairwise preferences
+// are aggregated with a Bradley-Terry model into one global ordering. Groups
+// run concurrently up to Config.MaxConcurrent without a failed group
+// cancelling the others, the config's retry and circuit breaker settings
+// apply to every call, and request metrics are recorded as for scoring.
+func NewRanker(cfg Config, ranking RankingConfig) (Ranker, error) {
+	if rankingPromptError != nil {
+		return nil, rankingPromptError
+	}
+
+	if ranking.GroupSize == 0 {
+		ranking.GroupSize = DefaultRankingGroupSize
+	}
+	if ranking.Rounds == 0 {
+		ranking.Rounds = DefaultRankingRounds
+	}
+	if ranking.GroupSize < 2 || ranking.GroupSize > maxRankingGroupSize {
+		return nil, fmt.Errorf("ranking GroupSize must be between 2 and %d", maxRankingGroupSize)
+	}
+	if ranking.Rounds < 1 {
+		return nil, errors.New("ranking Rounds must be positive")
+	}
+
+	s, err := newPerCallScorer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ranker{scorer: s, ranking: ranking}, nil
+}
+
+// Rank implements Ranker
+func (r *ranker) Rank(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]RankedItem, error) {
+	if items == nil {
+		return nil, errors.New("items cannot be nil")
+	}
+
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		if item.ID == "" {
+			return nil, fmt.Errorf("item at index %d has empty ID", i)
+		}
+		if seen[item.ID] {
+			return nil, fmt.Errorf("duplicate item ID %s at index %d", item.ID, i)
+		}
+		seen[item.ID] = true
+	}
+
+	if len(items) < 2 {
+		ranked := make([]RankedItem, len(items))
+		for i, item := range items {
+			ranked[i] = RankedItem{Item: item, Rank: 1}
+		}
+		return ranked, nil
+	}
+
+	options := &scoringOptions{
+		model:        r.scorer.config.Model,
+		promptText:   rankingPrompt,
+		systemPrompt: rankingSystemPrompt,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if err := r.scorer.config.validateOptions(options); err != nil {
+		return nil, err
+	}
+
+	groups := scheduleGroups(len(items), r.ranking)
+
+	slog.Info("Ranking text items",
+		"items", len(items),
+		"groups", len(groups),
+		"group_size", r.ranking.GroupSize,
+		"rounds", r.ranking.Rounds)
+
+	start := time.Now()
+	r.scorer.metrics.RecordBatchSize(len(items))
+
+	orderings, errs := runAll(ctx, len(groups), r.scorer.config.MaxConcurrent, func(ctx context.Context, i int) ([]int, error) {
+		return r.rankGroup(ctx, items, groups[i], options)
+	})
+
+	// Each group is a batch, and a failed one only takes its comparisons out
+	// of the fit; items it leaves with none keep an average strength
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			slog.Warn("Ranking group failed, fitting on the other groups",
+				"group", i,
+				"items", len(groups[i]),
+				"error", err)
+			failed = append(failed, &BatchError{Batch: i, Err: err})
+		}
+	}
+	err := errors.Join(failed...)
+	r.scorer.metrics.recordOutcome(r.scorer.config.resolveModel(options), start, err)
+	if len(failed) == len(groups) {
+		return nil, err
+	}
+
+	// wins[i][j] counts how often item i was preferred over item j
+	wins := make([][]float64, len(items))
+	for i := range wins {
+		wins[i] = make([]float64, len(items))
+	}
+	for _, ordering := range orderings {
+		for a := 0; a < len(ordering); a++ {
+			for b := a + 1; b < len(ordering); b++ {
+				wins[ordering[a]][ordering[b]]++
+			}
+		}
+	}
+
+	// Failed groups' batch errors are returned with the partial ranking
+	return rankFromWins(items, wins), err
+}
+
+// rankGroup asks the model to order one group and returns the group's item
+// indices from best to worst. IDs the model invents or repeats are ignored,
+// and items it leaves out take no part in this group's comparisons.
+func (r *ranker) rankGroup(ctx context.Context, items []TextItem, group []int, options *scoringOptions) ([]int, error) {
+	batch := make([]TextItem, len(group))
+	index := make(map[string]int, len(group))
+	for i, idx := range group {
+		batch[i] = items[idx]
+		index[items[idx].ID] = idx
+	}
+
+	prompt, err := r.scorer.formatPrompt(options.promptText, batch, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format prompt: %w", err)
+	}
+
+	schema, err := jsonschema.GenerateSchemaForType(rankingResponse{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JSON schema for ranking: %w", err)
+	}
+
+	resp, err := r.scorer.completeJSON(ctx, prompt, schema, options, len(batch))
+	if err != nil {
+		return nil, err
+	}
+
+	var response rankingResponse
+	if err := json.Unmarshal([]byte(resp.content), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response JSON: %w", err)
+	}
+
+	ordering := make([]int, 0, len(group))
+	used := make(map[string]bool, len(group))
+	for _, id := range response.Ranking {
+		idx, ok := index[id]
+		if !ok || used[id] {
+			slog.Warn("Ignoring unknown or repeated ID in ranking", "item_id", id)
+			continue
+		}
+		used[id] = true
+		ordering = append(ordering, idx)
+	}
+	if len(ordering) < len(group) {
+		slog.Warn("Ranking left out items", "expected", len(group), "ranked", len(ordering))
+	}
+
+	return ordering, nil
+}
+
+// scheduleGroups shuffles item indices into groups for each round. A
+// leftover single item joins the previous group so every item is compared.
+func scheduleGroups(n int, cfg RankingConfig) [][]int {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	var groups [][]int
+	for round := 0; round < cfg.Rounds; round++ {
+		order := rng.Perm(n)
+
+		var roundGroups [][]int
+		for start := 0; start < n; start += cfg.GroupSize {
+			roundGroups = append(roundGroups, order[start:min(start+cfg.GroupSize, n)])
+		}
+		if last := len(roundGroups) - 1; last > 0 && len(roundGroups[last]) == 1 {
+			roundGroups[last-1] = append(roundGroups[last-1], roundGroups[last][0])
+			roundGroups = roundGroups[:last]
+		}
+
+		groups = append(groups, roundGroups...)
+	}
+	return groups
+}
+
+// rankFromWins fits Bradley-Terry strengths to the win matrix with the
+// minorization-maximization algorithm and sorts items by strength. Every item
+// gets one virtual win and loss against an average opponent so items that
+// never lost or never won still get finite strengths.
+func rankFromWins(items []TextItem, wins [][]float64) []RankedItem {
+	const (
+		maxIterations = 200
+		tolerance     = 1e-9
+		prior         = 1.0
+	)
+
+	n := len(items)
+	strength := make([]float64, n)
+	for i := range strength {
+		strength[i] = 1
+	}
+
+	for iter := 0; iter < maxIterations; iter++ {
+		next := make([]float64, n)
+		var logSum float64
+
+		for i := 0; i < n; i++ {
+			won := prior
+			denominator := 2 * prior / (strength[i] + 1)
+			for j := 0; j < n; j++ {
+				if i == j {
+					continue
+				}
+				won += wins[i][j]
+				if games := wins[i][j] + wins[j][i]; games > 0 {
+					denominator += games / (strength[i] + strength[j])
+				}
+			}
+			next[i] = won / denominator
+			logSum += math.Log(next[i])
+		}
+
+		// Normalize to a geometric mean of 1 so 0 log-strength is average
+		scale := math.Exp(logSum / float64(n))
+		var change float64
+		for i := range next {
+			next[i] /= scale
+			change = math.Max(change, math.Abs(next[i]-strength[i]))
+		}
+		strength = next
+
+		if change < tolerance {
+			break
+		}
+	}
+
+	ranked := make([]RankedItem, n)
+	for i, item := range items {
+		ranked[i] = RankedItem{Item: item, Strength: math.Log(strength[i])}
+		for j := 0; j < n; j++ {
+			ranked[i].Wins += int(wins[i][j])
+			ranked[i].Comparisons += int(wins[i][j] + wins[j][i])
+		}
+	}
+
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return ranked[a].Strength > ranked[b].Strength
+	})
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+
+	return ranked
+}
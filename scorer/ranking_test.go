@@ -0,0 +1,236 @@
+// Package scorer_test covers tournament ranking: group scheduling, the
+// Bradley-Terry aggregation of group orderings, fitting on the groups that
+// succeed, request metrics, and input validation.
+package scorer_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Ranker", func() {
+	var (
+		ctx    context.Context
+		items  []scorer.TextItem
+		scores map[string]int
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scores = make(map[string]int)
+		items = nil
+		for i := 1; i <= 10; i++ {
+			id := fmt.Sprintf("%d", i)
+			items = append(items, scorer.TextItem{ID: id, Content: fmt.Sprintf("Post %d", i)})
+			scores[id] = i * 10
+		}
+	})
+
+	ids := func(ranked []scorer.RankedItem) []string {
+		out := make([]string, len(ranked))
+		for i, r := range ranked {
+			out[i] = r.Item.ID
+		}
+		return out
+	}
+
+	It("should recover the global order from group orderings", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(scores))
+		ranker, err := scorer.NewRanker(scorer.Config{Client: fake}, scorer.RankingConfig{GroupSize: 3, Rounds: 6, Seed: 7})
+		Expect(err).ToNot(HaveOccurred())
+
+		ranked, err := ranker.Rank(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ranked).To(HaveLen(10))
+
+		Expect(ranked[0].Item.ID).To(Equal("10"))
+		Expect(ranked[9].Item.ID).To(Equal("1"))
+		for i, r := range ranked {
+			Expect(r.Rank).To(Equal(i + 1))
+			Expect(r.Comparisons).To(BeNumerically(">", 0))
+			if i > 0 {
+				Expect(r.Strength).To(BeNumerically("<=", ranked[i-1].Strength))
+			}
+		}
+		Expect(ranked[0].Strength).To(BeNumerically(">", 0))
+		Expect(ranked[9].Strength).To(BeNumerically("<", 0))
+	})
+
+	It("should send the ranking prompt and schema", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(scores))
+		ranker, err := scorer.NewRanker(scorer.Config{Client: fake}, scorer.RankingConfig{GroupSize: 5, Rounds: 1})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = ranker.Rank(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		requests := fake.Requests()
+		Expect(requests).To(HaveLen(2))
+		for _, req := range requests {
+			Expect(req.Messages[0].Role).To(Equal(openai.ChatMessageRoleSystem))
+			Expect(req.Messages[0].Content).To(ContainSubstring("ranking"))
+			Expect(req.Messages[1].Content).To(ContainSubstring("Text items to rank"))
+			Expect(scorertest.ParseItems(req)).To(HaveLen(5))
+		}
+	})
+
+	It("should schedule groups reproducibly for a seed", func() {
+		run := func(seed int64) []string {
+			fake := scorertest.NewFakeClient()
+			ranker, err := scorer.NewRanker(scorer.Config{Client: fake, MaxConcurrent: 1}, scorer.RankingConfig{GroupSize: 4, Rounds: 2, Seed: seed})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = ranker.Rank(ctx, items)
+			Expect(err).ToNot(HaveOccurred())
+
+			var groups []string
+			for _, req := range fake.Requests() {
+				var group []string
+				for _, item := range scorertest.ParseItems(req) {
+					group = append(group, item.ID)
+				}
+				groups = append(groups, strings.Join(group, ","))
+			}
+			return groups
+		}
+
+		first := run(42)
+		Expect(run(42)).To(ConsistOf(first))
+		Expect(run(43)).ToNot(ConsistOf(first))
+	})
+
+	It("should merge a leftover single item into the previous group", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(scores))
+		ranker, err := scorer.NewRanker(scorer.Config{Client: fake}, scorer.RankingConfig{GroupSize: 3, Rounds: 1})
+		Expect(err).ToNot(HaveOccurred())
+
+		ranked, err := ranker.Rank(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(Equal(3))
+		for _, r := range ranked {
+			Expect(r.Comparisons).To(BeNumerically(">", 0))
+		}
+	})
+
+	It("should ignore unknown and repeated IDs in a group ordering", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"ranking":["b","ghost","b","a"],"reason":"b is better"}`
+			},
+		}
+		ranker, err := scorer.NewRanker(scorer.Config{Client: client}, scorer.RankingConfig{GroupSize: 2, Rounds: 1})
+		Expect(err).ToNot(HaveOccurred())
+
+		ranked, err := ranker.Rank(ctx, []scorer.TextItem{{ID: "a", Content: "A"}, {ID: "b", Content: "B"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ids(ranked)).To(Equal([]string{"b", "a"}))
+		Expect(ranked[0].Wins).To(Equal(1))
+		Expect(ranked[0].Comparisons).To(Equal(1))
+		Expect(ranked[1].Wins).To(Equal(0))
+	})
+
+	It("should fit the ranking on the groups that succeed when others fail", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(scores))
+		fake.FailNext(scorertest.Fault{Status: 400, Message: "bad request"})
+		ranker, err := scorer.NewRanker(scorer.Config{Client: fake}, scorer.RankingConfig{GroupSize: 3, Rounds: 6, Seed: 7})
+		Expect(err).ToNot(HaveOccurred())
+
+		before := requestsRecorded("error")
+		ranked, err := ranker.Rank(ctx, items)
+		Expect(err).To(MatchError(scorer.ErrBatchFailed))
+		Expect(err).To(MatchError(ContainSubstring("(batch 0)")))
+		Expect(err).To(MatchError(ContainSubstring("bad request")))
+		Expect(requestsRecorded("error") - before).To(Equal(1.0))
+
+		Expect(ranked).To(HaveLen(10))
+		Expect(ranked[0].Item.ID).To(Equal("10"))
+		Expect(ranked[9].Item.ID).To(Equal("1"))
+	})
+
+	It("should fail when every group fails", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{Status: 400, Message: "bad request"})
+		ranker, err := scorer.NewRanker(scorer.Config{Client: fake}, scorer.RankingConfig{})
+		Expect(err).ToNot(HaveOccurred())
+
+		ranked, err := ranker.Rank(ctx, items)
+		Expect(err).To(MatchError(ContainSubstring("bad request")))
+		Expect(ranked).To(BeNil())
+	})
+
+	It("should record request metrics", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(scores))
+		ranker, err := scorer.NewRanker(scorer.Config{Client: fake}, scorer.RankingConfig{})
+		Expect(err).ToNot(HaveOccurred())
+
+		before := requestsRecorded("success")
+		_, err = ranker.Rank(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requestsRecorded("success") - before).To(Equal(1.0))
+	})
+
+	It("should rank a single item first without calling the model", func() {
+		fake := scorertest.NewFakeClient()
+		ranker, err := scorer.NewRanker(scorer.Config{Client: fake}, scorer.RankingConfig{})
+		Expect(err).ToNot(HaveOccurred())
+
+		ranked, err := ranker.Rank(ctx, items[:1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ranked).To(HaveLen(1))
+		Expect(ranked[0].Rank).To(Equal(1))
+		Expect(fake.Calls()).To(Equal(0))
+	})
+
+	It("should reject invalid items", func() {
+		ranker, err := scorer.NewRanker(scorer.Config{Client: scorertest.NewFakeClient()}, scorer.RankingConfig{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = ranker.Rank(ctx, nil)
+		Expect(err).To(MatchError("items cannot be nil"))
+
+		_, err = ranker.Rank(ctx, []scorer.TextItem{{ID: "a"}, {ID: "a"}})
+		Expect(err).To(MatchError(ContainSubstring("duplicate item ID a")))
+
+		_, err = ranker.Rank(ctx, []scorer.TextItem{{ID: ""}})
+		Expect(err).To(MatchError(ContainSubstring("empty ID")))
+	})
+
+	It("should reject invalid tournament settings", func() {
+		_, err := scorer.NewRanker(scorer.Config{Client: scorertest.NewFakeClient()}, scorer.RankingConfig{GroupSize: 1})
+		Expect(err).To(MatchError(ContainSubstring("GroupSize must be between 2")))
+
+		_, err = scorer.NewRanker(scorer.Config{Client: scorertest.NewFakeClient()}, scorer.RankingConfig{Rounds: -1})
+		Expect(err).To(MatchError("ranking Rounds must be positive"))
+	})
+})
+
+// requestsRecorded sums the request counter for one status across models
+// from the default registry
+func requestsRecorded(status string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	var total float64
+	for _, family := range families {
+		if family.GetName() != "text_scorer_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "status" && label.GetValue() == status {
+					total += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,162 @@
+package scorer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// DefaultMaxReasks is how many follow-up requests are sent for items missing
+// from a response when Config.MaxReasks is zero
+const DefaultMaxReasks = 2
+
+// unscoredReason is the reason given to items the model never scored
+const unscoredReason = "Not scored: item missing from response"
+
+// resolveMaxReasks returns the configured re-ask limit, the default when unset,
+// or zero when re-asking is turned off
+func (c Config) resolveMaxReasks() int {
+	switch {
+	case c.MaxReasks < 0:
+		return 0
+	case c.MaxReasks == 0:
+		return DefaultMaxReasks
+	default:
+		return c.MaxReasks
+	}
+}
+
+// reconcileIDs matches response entries to batch items by ID. Entries for IDs
+// outside the batch are dropped, and an ID returned more than once is dropped
+// so the item is re-asked rather than taking whichever entry came last.
+func reconcileIDs[T any](items []TextItem, entries []T, itemID func(T) string, metrics *MetricsRecorder) map[string]T {
+	matched, unknown, duplicated := matchIDs(items, entries, itemID)
+	for _, id := range unknown {
+		slog.Warn("Response contains unknown item ID, ignoring", "item_id", id)
+		metrics.RecordResponseIDIssue("unknown")
+	}
+	for _, id := range duplicated {
+		slog.Warn("Response scores item more than once, discarding its scores", "item_id", id)
+		metrics.RecordResponseIDIssue("duplicate")
+	}
+
+	for _, item := range items {
+		if _, ok := matched[item.ID]; !ok {
+			metrics.RecordResponseIDIssue("missing")
+		}
+	}
+
+	return matched
+}
+
+// matchIDs matches response entries to batch items by ID as reconcileIDs does,
+// without logging or recording metrics, and returns the unknown IDs and the
+// IDs returned more than once alongside. Add-ons that read other fields of an
+// already reconciled response use it to pick the same entries.
+func matchIDs[T any](items []TextItem, entries []T, itemID func(T) string) (map[string]T, []string, []string) {
+	inBatch := make(map[string]bool, len(items))
+	for _, item := range items {
+		inBatch[item.ID] = true
+	}
+
+	matched := make(map[string]T, len(entries))
+	seen := make(map[string]bool, len(entries))
+	var unknown, duplicated []string
+	for _, entry := range entries {
+		id := itemID(entry)
+		if !inBatch[id] {
+			unknown = append(unknown, id)
+			continue
+		}
+		if seen[id] {
+			if _, ok := matched[id]; ok {
+				duplicated = append(duplicated, id)
+				delete(matched, id)
+			}
+			continue
+		}
+		seen[id] = true
+		matched[id] = entry
+	}
+
+	return matched, unknown, duplicated
+}
+
+// unscored returns the result for an item the response did not score
+func unscored(item TextItem) ScoredItem {
+	slog.Warn("Score not found for item", "item_id", item.ID)
+	return ScoredItem{Item: item, Reason: unscoredReason, Missing: true, Err: ErrItemMissing}
+}
+
+// processBatch scores a batch, splitting it when the response is truncated or
+// refused, then re-submits only the items missing from the response, up to the
+// configured re-ask limit. Items the model never scores are returned with
+// Missing set.
+func (s *scorer) processBatch(ctx context.Context, batch []TextItem, options *scoringOptions) ([]ScoredItem, error) {
+	results, err := s.scoreSplitting(ctx, batch, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return reaskMissing(ctx, s, results, func(result ScoredItem) bool {
+		return errors.Is(result.Err, ErrItemMissing)
+	}, func(result ScoredItem) TextItem {
+		return result.Item
+	}, func(ctx context.Context, missing []TextItem) ([]ScoredItem, error) {
+		return s.scoreSplitting(ctx, missing, options)
+	})
+}
+
+// reaskMissing re-submits the items whose results are missing through request,
+// up to the configured re-ask limit, and replaces each missing result with the
+// follow-up's result once it is no longer missing. A failed follow-up leaves
+// the items missing unless the context is done.
+func reaskMissing[R any](ctx context.Context, s *scorer, results []R, missing func(R) bool, itemOf func(R) TextItem,
+	request func(context.Context, []TextItem) ([]R, error)) ([]R, error) {
+	maxReasks := s.config.resolveMaxReasks()
+	for attempt := 1; ; attempt++ {
+		var items []TextItem
+		var positions []int
+		for i, result := range results {
+			if missing(result) {
+				items = append(items, itemOf(result))
+				positions = append(positions, i)
+			}
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		if attempt > maxReasks {
+			slog.Warn("Items still missing after re-asking, leaving them unscored",
+				"items", len(items),
+				"reasks", maxReasks)
+			break
+		}
+
+		slog.Info("Re-asking for items missing from response",
+			"items", len(items),
+			"attempt", attempt)
+		s.metrics.RecordReask()
+
+		retried, err := request(ctx, items)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			// The batch already has its results, so a failed follow-up only
+			// leaves the missing items missing
+			slog.Warn("Re-ask failed, leaving items unscored",
+				"items", len(items),
+				"error", err)
+			break
+		}
+		for j, result := range retried {
+			if !missing(result) {
+				results[positions[j]] = result
+			}
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,151 @@
+// Package scorer_test covers response reconciliation: re-asking for items the
+// model left out or scored twice, ignoring unknown IDs, and leaving items
+// unscored once the re-ask limit is reached.
+package scorer_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Response reconciliation", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Jazz trio at the Blue Note, Friday 8pm"},
+			{ID: "2", Content: "Anyone know a good plumber?"},
+			{ID: "3", Content: "Open mic night at the Crown, Thursday"},
+		}
+	})
+
+	It("should re-ask for missing items only", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 90, "2": 10, "3": 70}))
+		fake.FailNext(scorertest.Fault{MissingIDs: []string{"2"}})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[1].Score).To(Equal(10))
+		Expect(results[1].Missing).To(BeFalse())
+		Expect(results[0].Score).To(Equal(90))
+		Expect(results[2].Score).To(Equal(70))
+
+		requests := fake.Requests()
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Messages[1].Content).To(ContainSubstring("(ID: 2)"))
+		Expect(requests[1].Messages[1].Content).ToNot(ContainSubstring("(ID: 1)"))
+		Expect(requests[1].Messages[1].Content).ToNot(ContainSubstring("(ID: 3)"))
+	})
+
+	It("should leave items unscored once the re-ask limit is reached", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{MissingIDs: []string{"3"}})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()).To(HaveLen(1 + scorer.DefaultMaxReasks))
+
+		Expect(results[2].Missing).To(BeTrue())
+		Expect(results[2].Score).To(Equal(0))
+		Expect(results[2].Value).To(Equal(0.0))
+		Expect(results[2].Reason).To(ContainSubstring("Not scored"))
+		Expect(results[0].Missing).To(BeFalse())
+	})
+
+	It("should honour the configured re-ask limit", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{MissingIDs: []string{"3"}})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithMaxReasks(4))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()).To(HaveLen(5))
+	})
+
+	It("should not re-ask when re-asking is turned off", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{MissingIDs: []string{"3"}})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithMaxReasks(-1))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()).To(HaveLen(1))
+		Expect(results[2].Missing).To(BeTrue())
+	})
+
+	It("should re-ask for items scored more than once and ignore unknown IDs", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				if !containsID(req, "1") {
+					return `{"version":"1.0","scores":[{"item_id":"2","score":15,"reason":"re-asked"}]}`
+				}
+				return `{"version":"1.0","scores":[
+					{"item_id":"1","score":90,"reason":"jazz"},
+					{"item_id":"2","score":10,"reason":"first"},
+					{"item_id":"2","score":80,"reason":"second"},
+					{"item_id":"3","score":70,"reason":"open mic"},
+					{"item_id":"99","score":100,"reason":"invented"}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(3))
+		Expect(client.requests).To(HaveLen(2))
+		Expect(results[1].Score).To(Equal(15))
+		Expect(results[1].Reason).To(Equal("re-asked"))
+		Expect(results[0].Score).To(Equal(90))
+	})
+
+	It("should leave items unscored when the re-ask fails", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{MissingIDs: []string{"2"}}, scorertest.Fault{Status: 500})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()).To(HaveLen(2))
+		Expect(results[1].Missing).To(BeTrue())
+		Expect(results[0].Missing).To(BeFalse())
+	})
+
+	It("should re-ask for items missing from rubric scores", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{MissingIDs: []string{"1"}})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithRubric(scorer.Rubric{
+			Dimensions: []scorer.Dimension{{Name: "relevance"}},
+		}))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Requests()).To(HaveLen(2))
+		Expect(results[0].Missing).To(BeFalse())
+		Expect(results[0].Dimensions).To(HaveKey("relevance"))
+	})
+})
+
+// containsID reports whether a scoring request's prompt includes the item ID
+func containsID(req openai.ChatCompletionRequest, id string) bool {
+	return strings.Contains(req.Messages[len(req.Messages)-1].Content, "(ID: "+id+")")
+}
@@ -0,0 +1,211 @@
+package scorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultMaxJSONCorrections is how many follow-up requests ask the model to
+// correct a response that is not valid JSON when Config.MaxJSONCorrections is
+// zero
+const DefaultMaxJSONCorrections = 1
+
+// Kinds of JSON recovery, used as the kind label of the recovery metric
+const (
+	recoveryCodeFence     = "code_fence"
+	recoveryTrailingComma = "trailing_comma"
+	recoveryPartialArray  = "partial_array"
+	recoveryReprompt      = "reprompt"
+)
+
+// resolveMaxJSONCorrections returns the configured correction limit, the
+// default when unset, or zero when corrections are turned off
+func (c Config) resolveMaxJSONCorrections() int {
+	switch {
+	case c.MaxJSONCorrections < 0:
+		return 0
+	case c.MaxJSONCorrections == 0:
+		return DefaultMaxJSONCorrections
+	default:
+		return c.MaxJSONCorrections
+	}
+}
+
+// repairJSON returns content as valid JSON, applying lenient repairs when it
+// does not parse as-is: stripping markdown code fences, dropping trailing
+// commas, and cutting a cut-off response back to its last complete array
+// element. The kinds of repair applied are returned alongside. When nothing
+// makes it parse, the error is the one from the original content.
+func repairJSON(content string) (string, []string, error) {
+	parseErr := checkJSON(content)
+	if parseErr == nil {
+		return content, nil, nil
+	}
+
+	var kinds []string
+	repaired := content
+	steps := []struct {
+		kind string
+		fix  func(string) string
+	}{
+		{recoveryCodeFence, stripCodeFences},
+		{recoveryTrailingComma, removeTrailingCommas},
+		{recoveryPartialArray, closePartialArray},
+	}
+	for _, step := range steps {
+		fixed := step.fix(repaired)
+		if fixed == repaired {
+			continue
+		}
+		repaired = fixed
+		kinds = append(kinds, step.kind)
+		if checkJSON(repaired) == nil {
+			return repaired, kinds, nil
+		}
+	}
+
+	return content, nil, parseErr
+}
+
+// checkJSON returns the error from decoding content, or nil when it is valid JSON
+func checkJSON(content string) error {
+	var data any
+	return json.Unmarshal([]byte(content), &data)
+}
+
+// stripCodeFences removes a markdown code fence, with or without a language
+// tag, wrapped around the JSON
+func stripCodeFences(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "```") {
+		return content
+	}
+
+	// Drop the opening fence line, including any language tag
+	newline := strings.IndexByte(trimmed, '\n')
+	if newline < 0 {
+		return content
+	}
+	body := trimmed[newline+1:]
+	if end := strings.LastIndex(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	return strings.TrimSpace(body)
+}
+
+// removeTrailingCommas drops commas followed only by whitespace before a
+// closing brace or bracket, leaving string contents alone
+func removeTrailingCommas(content string) string {
+	var out strings.Builder
+	out.Grow(len(content))
+
+	inString, escaped := false, false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			out.WriteByte(c)
+			continue
+		}
+
+		if c == ',' {
+			next := i + 1
+			for next < len(content) && strings.IndexByte(" \t\r\n", content[next]) >= 0 {
+				next++
+			}
+			if next < len(content) && (content[next] == '}' || content[next] == ']') {
+				continue
+			}
+		}
+		if c == '"' {
+			inString = true
+		}
+		out.WriteByte(c)
+	}
+
+	return out.String()
+}
+
+// closePartialArray cuts a response that stops part-way through back to the
+// end of the last complete array element, then closes every bracket and brace
+// still open. Content without a complete array element is returned unchanged,
+// since an empty array recovers nothing.
+func closePartialArray(content string) string {
+	var open []byte    // Closing characters for the containers currently open
+	var closers []byte // open as it was after the last complete array element
+	cut := -1          // Offset just past the last complete array element
+	inString, escaped := false, false
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				if len(open) > 0 && open[len(open)-1] == ']' {
+					cut, closers = i+1, append(closers[:0], open...)
+				}
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			open = append(open, '}')
+		case '[':
+			open = append(open, ']')
+		case '}', ']':
+			if len(open) == 0 || open[len(open)-1] != c {
+				return content
+			}
+			open = open[:len(open)-1]
+			if len(open) > 0 && open[len(open)-1] == ']' {
+				cut, closers = i+1, append(closers[:0], open...)
+			}
+		}
+	}
+
+	if cut < 0 || len(open) == 0 {
+		return content
+	}
+
+	var out strings.Builder
+	out.WriteString(content[:cut])
+	for i := len(closers) - 1; i >= 0; i-- {
+		out.WriteByte(closers[i])
+	}
+	return out.String()
+}
+
+// correctionMessages builds the follow-up turns that show the model its
+// invalid response and the parse error, and ask for the JSON again
+func correctionMessages(content string, parseErr error) []openai.ChatCompletionMessage {
+	return []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleAssistant,
+			Content: content,
+		},
+		{
+			Role: openai.ChatMessageRoleUser,
+			Content: fmt.Sprintf("Your previous response was not valid JSON: %v. "+
+				"Reply with the complete corrected JSON only, matching the required format, "+
+				"with no code fences or other text.", parseErr),
+		},
+	}
+}
@@ -0,0 +1,154 @@
+// Package scorer_test covers malformed response recovery: lenient repair of
+// code fences, trailing commas and cut-off arrays, and asking the model to
+// correct JSON that cannot be repaired.
+package scorer_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Malformed JSON recovery", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Jazz trio at the Blue Note, Friday 8pm"},
+			{ID: "2", Content: "Anyone know a good plumber?"},
+		}
+	})
+
+	DescribeTable("lenient repair",
+		func(content string) {
+			client := &mockScoringClient{
+				respond: func(req openai.ChatCompletionRequest) string { return content },
+			}
+			s, err := scorer.NewScorer(scorer.Config{Client: client})
+			Expect(err).ToNot(HaveOccurred())
+
+			results, err := s.ScoreTexts(ctx, items)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.requests).To(HaveLen(1))
+			Expect(results[0].Score).To(Equal(90))
+			Expect(results[1].Score).To(Equal(10))
+			Expect(results[1].Reason).To(Equal("plumbing, not an event"))
+		},
+		Entry("code fence with a language tag", "```json\n"+`{"version":"1.0","scores":[
+			{"item_id":"1","score":90,"reason":"jazz"},
+			{"item_id":"2","score":10,"reason":"plumbing, not an event"}]}`+"\n```"),
+		Entry("trailing commas", `{"version":"1.0","scores":[
+			{"item_id":"1","score":90,"reason":"jazz",},
+			{"item_id":"2","score":10,"reason":"plumbing, not an event"},
+		],}`),
+		Entry("code fence and trailing commas", "```\n"+`{"version":"1.0","scores":[
+			{"item_id":"1","score":90,"reason":"jazz"},
+			{"item_id":"2","score":10,"reason":"plumbing, not an event"},]}`+"\n```"),
+	)
+
+	It("should leave commas inside strings alone", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[
+					{"item_id":"1","score":90,"reason":"odd ,} text"},
+					{"item_id":"2","score":10,"reason":"also ,] odd"},]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Reason).To(Equal("odd ,} text"))
+		Expect(results[1].Reason).To(Equal("also ,] odd"))
+	})
+
+	It("should keep the complete entries of a cut-off array and re-ask for the rest", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				if containsID(req, "1") {
+					return `{"version":"1.0","scores":[
+						{"item_id":"1","score":90,"reason":"jazz"},
+						{"item_id":"2","score":10,"reas`
+				}
+				return `{"version":"1.0","scores":[{"item_id":"2","score":15,"reason":"re-asked"}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requests).To(HaveLen(2))
+		Expect(results[0].Score).To(Equal(90))
+		Expect(results[1].Score).To(Equal(15))
+	})
+
+	It("should send the parse error back and use the corrected JSON", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 90, "2": 10}))
+		fake.FailNext(scorertest.Fault{MalformedJSON: true})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(90))
+		Expect(results[1].Score).To(Equal(10))
+
+		requests := fake.Requests()
+		Expect(requests).To(HaveLen(2))
+		correction := requests[1].Messages
+		Expect(correction).To(HaveLen(4))
+		Expect(correction[1].Content).To(Equal(requests[0].Messages[1].Content))
+		Expect(correction[2].Role).To(Equal(openai.ChatMessageRoleAssistant))
+		Expect(correction[2].Content).To(ContainSubstring("not json"))
+		Expect(correction[3].Role).To(Equal(openai.ChatMessageRoleUser))
+		Expect(correction[3].Content).To(ContainSubstring("not valid JSON: invalid character"))
+	})
+
+	It("should fail once the correction limit is reached", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{MalformedJSON: true})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithMaxJSONCorrections(3))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).To(MatchError(ContainSubstring("failed to parse response JSON")))
+		Expect(fake.Calls()).To(Equal(4))
+	})
+
+	It("should not ask for corrections when they are turned off", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{MalformedJSON: true})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithMaxJSONCorrections(-1))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).To(MatchError(ContainSubstring("failed to parse response JSON")))
+		Expect(fake.Calls()).To(Equal(1))
+	})
+
+	It("should correct classification responses too", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{MalformedJSON: true})
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: fake}, scorer.LabelSet{Labels: []scorer.Label{
+			{Name: "event"},
+			{Name: "other"},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+		Expect(fake.Calls()).To(Equal(2))
+	})
+})
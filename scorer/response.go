@@ -0,0 +1,114 @@
+package scorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Response errors, returned when a completion cannot be used
+var (
+	ErrNoChoices       = errors.New("response contained no choices")
+	ErrOutputTruncated = errors.New("response truncated at the output token limit")
+)
+
+// RefusalError reports that the model declined to answer. ItemID is set once
+// the refusal has been narrowed down to a single item.
+type RefusalError struct {
+	ItemID string // Item the model refused to score; empty for a whole request
+	Reason string // Refusal message from the model, or the finish reason
+}
+
+// Error implements the error interface
+func (e *RefusalError) Error() string {
+	if e.ItemID == "" {
+		return fmt.Sprintf("model refused the request: %s", e.Reason)
+	}
+	return fmt.Sprintf("model refused to score item %s: %s", e.ItemID, e.Reason)
+}
+
+// refusedReason is the reason given to items the model refused to score
+const refusedReason = "Not scored: model refused to score the item"
+
+// checkChoices returns the first choice of a response, or an error when there
+// is none, the model refused or a content filter stopped it, or the output was
+// cut off at the token limit
+func checkChoices(resp openai.ChatCompletionResponse) (openai.ChatCompletionChoice, error) {
+	if len(resp.Choices) == 0 {
+		return openai.ChatCompletionChoice{}, ErrNoChoices
+	}
+
+	choice := resp.Choices[0]
+	switch {
+	case choice.Message.Refusal != "":
+		return choice, &RefusalError{Reason: choice.Message.Refusal}
+	case choice.FinishReason == openai.FinishReasonContentFilter:
+		return choice, &RefusalError{Reason: "stopped by content filter"}
+	case choice.FinishReason == openai.FinishReasonLength:
+		return choice, ErrOutputTruncated
+	}
+	return choice, nil
+}
+
+// scoreSplitting scores a batch, halving it and scoring each half whenever the
+// response is truncated or refused. A refusal that narrows down to one item
+// leaves that item unscored with a *RefusalError, while a single item whose
+// output still does not fit fails the batch.
+func (s *scorer) scoreSplitting(ctx context.Context, batch []TextItem, options *scoringOptions) ([]ScoredItem, error) {
+	return splitUnusable(ctx, s, batch, func(ctx context.Context, batch []TextItem) ([]ScoredItem, error) {
+		return s.scoreBatch(ctx, batch, options)
+	}, func(item TextItem, refusal *RefusalError) ScoredItem {
+		return ScoredItem{Item: item, Reason: refusedReason, Missing: true, Err: refusal}
+	})
+}
+
+// splitUnusable sends a batch through request, halving it and sending each
+// half whenever the response is truncated or refused. A refusal that narrows
+// down to one item gives that item the result from refused, while a single
+// item whose output still does not fit fails the batch.
+func splitUnusable[R any](ctx context.Context, s *scorer, batch []TextItem,
+	request func(context.Context, []TextItem) ([]R, error), refused func(TextItem, *RefusalError) R) ([]R, error) {
+	results, err := request(ctx, batch)
+	if err == nil {
+		return results, nil
+	}
+
+	var refusal *RefusalError
+	isRefusal := errors.As(err, &refusal)
+	if !isRefusal && !errors.Is(err, ErrOutputTruncated) {
+		return nil, err
+	}
+
+	if len(batch) == 1 {
+		if !isRefusal {
+			return nil, fmt.Errorf("item %s: %w", batch[0].ID, err)
+		}
+		slog.Warn("Model refused item, leaving it unanswered",
+			"item_id", batch[0].ID,
+			"reason", refusal.Reason)
+		return []R{refused(batch[0], &RefusalError{ItemID: batch[0].ID, Reason: refusal.Reason})}, nil
+	}
+
+	cause := "truncated"
+	if isRefusal {
+		cause = "refused"
+	}
+	slog.Info("Splitting batch after unusable response",
+		"batch_size", len(batch),
+		"cause", cause)
+	s.metrics.RecordBatchSplit(cause)
+
+	mid := len(batch) / 2
+	first, err := splitUnusable(ctx, s, batch[:mid], request, refused)
+	if err != nil {
+		return nil, err
+	}
+	second, err := splitUnusable(ctx, s, batch[mid:], request, refused)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
@@ -0,0 +1,161 @@
+// Package scorer_test covers unusable responses: splitting batches whose output
+// was truncated, narrowing refusals down to the refused item, and empty
+// choice lists.
+package scorer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Unusable responses", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = make([]scorer.TextItem, 6)
+		for i := range items {
+			items[i] = scorer.TextItem{ID: fmt.Sprintf("%d", i+1), Content: fmt.Sprintf("Post %d", i+1)}
+		}
+	})
+
+	It("should split truncated batches until the output fits", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithOutputLimit(2))
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(6))
+		for i, result := range results {
+			Expect(result.Item.ID).To(Equal(items[i].ID))
+			Expect(result.Missing).To(BeFalse())
+			Expect(result.Score).To(Equal(scorertest.DefaultScore))
+		}
+
+		// 6 items, then halves of 3, then 1 and 2 from each half
+		Expect(fake.Calls()).To(Equal(7))
+	})
+
+	It("should fail when a single item's output is still truncated", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{Truncate: true})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items[:2])
+		Expect(err).To(MatchError(scorer.ErrOutputTruncated))
+		Expect(err).To(MatchError(ContainSubstring("item 1")))
+		Expect(fake.Calls()).To(Equal(2))
+	})
+
+	It("should leave only the refused item unscored with a typed error", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithRefusals(map[string]string{"4": "I can't help with that."}))
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(6))
+
+		var refusal *scorer.RefusalError
+		Expect(errors.As(results[3].Err, &refusal)).To(BeTrue())
+		Expect(refusal.ItemID).To(Equal("4"))
+		Expect(refusal.Reason).To(Equal("I can't help with that."))
+		Expect(results[3].Missing).To(BeTrue())
+		Expect(results[3].Score).To(Equal(0))
+
+		for i, result := range results {
+			if i != 3 {
+				Expect(result.Missing).To(BeFalse())
+				Expect(result.Err).ToNot(HaveOccurred())
+			}
+		}
+
+		// 6 items, halves of 3, then 1 and 2 from the refused half; no re-asks
+		Expect(fake.Calls()).To(Equal(5))
+	})
+
+	It("should report refusals as item failures in partial results", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithRefusals(map[string]string{"2": "No."}))
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := scorer.ScoreTextsPartial(ctx, s, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Scored).To(HaveLen(5))
+		Expect(result.Failed).To(HaveLen(1))
+		Expect(result.Failed[0].Item.ID).To(Equal("2"))
+
+		var refusal *scorer.RefusalError
+		Expect(errors.As(result.Failed[0].Err, &refusal)).To(BeTrue())
+		Expect(result.Err()).To(MatchError(ContainSubstring("model refused to score item 2: No.")))
+	})
+
+	It("should return an error for a response without choices", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{NoChoices: true})
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).To(MatchError(scorer.ErrNoChoices))
+		Expect(fake.Calls()).To(Equal(1))
+	})
+
+	It("should narrow classification refusals down to the refused item", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithRefusals(map[string]string{"4": "No."}))
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: fake}, scorer.LabelSet{Labels: []scorer.Label{
+			{Name: "event"},
+			{Name: "other"},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(6))
+
+		var refusal *scorer.RefusalError
+		Expect(errors.As(results[3].Err, &refusal)).To(BeTrue())
+		Expect(refusal.ItemID).To(Equal("4"))
+		Expect(results[3].Missing).To(BeTrue())
+		Expect(results[3].Labels).To(BeEmpty())
+
+		for i, result := range results {
+			if i != 3 {
+				Expect(result.Missing).To(BeFalse())
+				Expect(result.Labels).ToNot(BeEmpty())
+			}
+		}
+
+		// Split as for scoring, with no re-asks for the refused item
+		Expect(fake.Calls()).To(Equal(5))
+	})
+
+	It("should fail the batch when a single item's classification is still truncated", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{Truncate: true})
+		classifier, err := scorer.NewClassifier(scorer.Config{Client: fake}, scorer.LabelSet{Labels: []scorer.Label{
+			{Name: "event"},
+			{Name: "other"},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := classifier.Classify(ctx, items[:2])
+		Expect(err).To(MatchError(scorer.ErrOutputTruncated))
+		Expect(err).To(MatchError(scorer.ErrBatchFailed))
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Missing).To(BeTrue())
+		Expect(fake.Calls()).To(Equal(2))
+	})
+})
@@ -203,14 +203,14 @@ func NewRetryScorer(scorer Scorer, config *RetryConfig) Scorer {
 
 // ScoreTexts implements Scorer interface with retry logic
 func (s *retryScorer) ScoreTexts(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ScoredItem, error) {
-	return s.retryOperation(ctx, func() ([]ScoredItem, error) {
+	return s.retryOperation(ctx, items, func(items []TextItem) ([]ScoredItem, error) {
 		return s.scorer.ScoreTexts(ctx, items, opts...)
 	})
 }
 
 // ScoreTextsWithOptions implements Scorer interface with retry logic
 func (s *retryScorer) ScoreTextsWithOptions(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ScoredItem, error) {
-	return s.retryOperation(ctx, func() ([]ScoredItem, error) {
+	return s.retryOperation(ctx, items, func(items []TextItem) ([]ScoredItem, error) {
 		return s.scorer.ScoreTextsWithOptions(ctx, items, opts...)
 	})
 }
@@ -221,60 +221,87 @@ func (s *retryScorer) GetHealth(ctx context.Context) HealthStatus {
 	return s.scorer.GetHealth(ctx)
 }
 
-// retryOperation performs an operation with retry logic
-func (s *retryScorer) retryOperation(ctx context.Context, operation func() ([]ScoredItem, error)) ([]ScoredItem, error) {
-	var lastErr error
+// retryOperation scores items with retry logic. When an attempt returns
+// results alongside its error, each failed batch is judged by its own error:
+// only the items of retryable batches are sent again, while the batches that
+// succeeded or failed for good are kept.
+func (s *retryScorer) retryOperation(ctx context.Context, items []TextItem, operation func(items []TextItem) ([]ScoredItem, error)) ([]ScoredItem, error) {
+	var results []ScoredItem
+	var positions []int
 	var attempts int
 
 	wrapper := &RetryWrapper{config: s.config}
 	backoff := wrapper.getBackoffStrategy()
 
+	// finish returns the error of the last attempt, or the remaining batch
+	// errors once some batches have been kept
+	finish := func(err error) ([]ScoredItem, error) {
+		if results == nil {
+			return nil, err
+		}
+		return results, batchErrors(results)
+	}
+
+	pending := items
 	for {
 		attempts++
 
 		// Try the operation
-		result, err := operation()
+		result, err := operation(pending)
+		if results == nil {
+			results = result
+		} else {
+			mergeRescored(results, positions, result, err)
+		}
+		if results != nil {
+			// Batches kept from earlier attempts still count as failed
+			err = batchErrors(results)
+		}
 		if err == nil {
 			if attempts > 1 {
 				slog.Info("Text scoring succeeded after retry",
 					"attempts", attempts)
 			}
-			return result, nil
+			return results, nil
 		}
 
-		lastErr = err
-
-		// Check if error is retryable
-		if !IsRetryableError(err) {
+		// Check if error is retryable, batch by batch when there are results
+		retryable := IsRetryableError(err)
+		if results != nil {
+			pending, positions = failedBatchItems(results, IsRetryableError)
+			retryable = len(pending) > 0
+		}
+		if !retryable {
 			slog.Debug("Non-retryable error in text scoring",
 				"error", err,
 				"attempts", attempts)
-			return nil, err
+			return finish(err)
 		}
 
 		// Check if we've exceeded max attempts
 		if attempts >= s.config.MaxAttempts {
 			slog.Warn("Max retry attempts reached for text scoring",
 				"attempts", attempts,
-				"error", lastErr)
-			return nil, lastErr
+				"error", err)
+			return finish(err)
 		}
 
 		// Calculate next delay
 		delay, stop := backoff.Next()
 		if stop {
-			return nil, lastErr
+			return finish(err)
 		}
 
 		slog.Debug("Retrying text scoring after delay",
 			"attempt", attempts,
+			"items", len(pending),
 			"delay", delay,
 			"error", err)
 
 		// Wait with context awareness
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return finish(ctx.Err())
 		case <-time.After(delay):
 			// Continue to next retry
 		}
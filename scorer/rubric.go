@@ -0,0 +1,285 @@
+package scorer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// dimensionNamePattern restricts dimension names to safe JSON property names
+var dimensionNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Dimension is one named criterion of a rubric, scored on its own scale
+type Dimension struct {
+	Name        string // JSON property name, e.g. "relevance"
+	Description string // What the model should assess for this dimension
+	Min         int    // Lowest score on the scale
+	Max         int    // Highest score on the scale (Min and Max both 0 means 0-100)
+	// Weight is the dimension's relative weight in the aggregate. nil means
+	// 1; zero keeps the dimension scored but leaves it out of the aggregate
+	// (see WithWeight).
+	Weight *float64
+}
+
+// WithWeight sets the dimension's weight in the aggregate. Unlike leaving
+// Weight nil, zero is kept and leaves the dimension out of the aggregate.
+func (d Dimension) WithWeight(weight float64) Dimension {
+	d.Weight = &weight
+	return d
+}
+
+// Rubric scores items on several named dimensions and combines them into a
+// weighted aggregate. ScoredItem.Dimensions carries each dimension's score and
+// reason; ScoredItem.Aggregate carries the combined result on a 0-100 scale,
+// and ScoredItem.Score and ScoredItem.Value carry it on the configured scale.
+type Rubric struct {
+	Dimensions []Dimension
+}
+
+// DimensionScore is an item's score on one rubric dimension
+type DimensionScore struct {
+	Score  int    // Score on the dimension's scale
+	Reason string // AI explanation for this dimension
+}
+
+// Internal response types for rubric JSON parsing
+type rubricResponse struct {
+	Version string            `json:"version"`
+	Scores  []rubricScoreItem `json:"scores"`
+}
+
+type rubricScoreItem struct {
+	ItemID     string                         `json:"item_id"`
+	Dimensions map[string]rubricDimensionItem `json:"dimensions"`
+	Reason     string                         `json:"reason"`
+}
+
+type rubricDimensionItem struct {
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// WithRubric scores this request against a rubric instead of a single score
+func WithRubric(rubric Rubric) ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.rubric = &rubric
+	}
+}
+
+// Validate checks that the rubric is usable
+func (r Rubric) Validate() error {
+	if len(r.Dimensions) == 0 {
+		return errors.New("rubric must have at least one dimension")
+	}
+
+	seen := make(map[string]bool, len(r.Dimensions))
+	var weights float64
+	for _, d := range r.Dimensions {
+		if !dimensionNamePattern.MatchString(d.Name) {
+			return fmt.Errorf("invalid rubric dimension name %q: use lowercase letters, digits and underscores", d.Name)
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("duplicate rubric dimension: %s", d.Name)
+		}
+		seen[d.Name] = true
+
+		min, max := d.scale()
+		if min >= max {
+			return fmt.Errorf("rubric dimension %s: Min must be less than Max", d.Name)
+		}
+		if d.weight() < 0 {
+			return fmt.Errorf("rubric dimension %s: Weight must be non-negative", d.Name)
+		}
+		weights += d.weight()
+	}
+
+	if weights == 0 {
+		return errors.New("rubric must have at least one dimension with a positive weight")
+	}
+
+	return nil
+}
+
+// Aggregate combines dimension scores into a weighted score on a 0-100 scale.
+// Each dimension is normalized to its scale before weighting; dimensions
+// missing from scores count as their minimum.
+func (r Rubric) Aggregate(scores map[string]DimensionScore) float64 {
+	var total, weights float64
+	for _, d := range r.Dimensions {
+		min, max := d.scale()
+		weight := d.weight()
+
+		var normalized float64
+		if score, ok := scores[d.Name]; ok {
+			normalized = float64(clampInt(score.Score, min, max)-min) / float64(max-min)
+		}
+
+		total += weight * normalized
+		weights += weight
+	}
+
+	if weights == 0 {
+		return 0
+	}
+	return total / weights * 100
+}
+
+// scale returns the dimension's bounds, defaulting to 0-100
+func (d Dimension) scale() (int, int) {
+	if d.Min == 0 && d.Max == 0 {
+		return 0, 100
+	}
+	return d.Min, d.Max
+}
+
+// weight returns the dimension's weight, defaulting to 1 when unset
+func (d Dimension) weight() float64 {
+	if d.Weight == nil {
+		return 1
+	}
+	return *d.Weight
+}
+
+// schema builds the response schema for the rubric: one object per item with
+// a score and reason for every dimension, plus an overall reason
+func (r Rubric) schema() *jsonschema.Definition {
+	dimensionProps := make(map[string]jsonschema.Definition, len(r.Dimensions))
+	dimensionNames := make([]string, 0, len(r.Dimensions))
+	for _, d := range r.Dimensions {
+		min, max := d.scale()
+		dimensionProps[d.Name] = jsonschema.Definition{
+			Type:        jsonschema.Object,
+			Description: fmt.Sprintf("Integer score from %d to %d", min, max),
+			Properties: map[string]jsonschema.Definition{
+				"score":  {Type: jsonschema.Integer},
+				"reason": {Type: jsonschema.String},
+			},
+			Required:             []string{"score", "reason"},
+			AdditionalProperties: false,
+		}
+		dimensionNames = append(dimensionNames, d.Name)
+	}
+
+	return &jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"version": {Type: jsonschema.String},
+			"scores": {
+				Type: jsonschema.Array,
+				Items: &jsonschema.Definition{
+					Type: jsonschema.Object,
+					Properties: map[string]jsonschema.Definition{
+						"item_id": {Type: jsonschema.String},
+						"dimensions": {
+							Type:                 jsonschema.Object,
+							Properties:           dimensionProps,
+							Required:             dimensionNames,
+							AdditionalProperties: false,
+						},
+						"reason": {Type: jsonschema.String},
+					},
+					Required:             []string{"item_id", "dimensions", "reason"},
+					AdditionalProperties: false,
+				},
+			},
+		},
+		Required:             []string{"version", "scores"},
+		AdditionalProperties: false,
+	}
+}
+
+// instructions describes the rubric to the model
+func (r Rubric) instructions() string {
+	var sb strings.Builder
+	sb.WriteString("Instead of a single score, score every item on each of these dimensions, ")
+	sb.WriteString("using each dimension's own scale, with a short reason per dimension and an overall reason:\n")
+	for _, d := range r.Dimensions {
+		min, max := d.scale()
+		sb.WriteString(fmt.Sprintf("- %s (%d-%d)", d.Name, min, max))
+		if d.Description != "" {
+			sb.WriteString(": " + d.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// resolveRubric returns the rubric for a request: the per-request option,
+// then the config default, or nil for single-score mode
+func (c Config) resolveRubric(options *scoringOptions) *Rubric {
+	if options != nil && options.rubric != nil {
+		return options.rubric
+	}
+	return c.Rubric
+}
+
+// mapRubricScoresToItems matches rubric scores to input items by ID, clamping
+// each dimension to its scale and computing the aggregate, which Score and
+// Value map onto the configured score scale. Items missing from the response
+// are returned with Missing set, as in single-score mode.
+func (s *scorer) mapRubricScoresToItems(items []TextItem, scores []rubricScoreItem, rubric *Rubric) []ScoredItem {
+	scale := s.config.resolveScale()
+
+	scoreMap := reconcileIDs(items, scores, func(score rubricScoreItem) string { return score.ItemID }, s.metrics)
+
+	results := make([]ScoredItem, len(items))
+	for i, item := range items {
+		score, found := scoreMap[item.ID]
+		if !found {
+			results[i] = unscored(item)
+			continue
+		}
+
+		dimensions := make(map[string]DimensionScore, len(rubric.Dimensions))
+		for _, d := range rubric.Dimensions {
+			raw, ok := score.Dimensions[d.Name]
+			if !ok {
+				slog.Warn("Dimension missing from response",
+					"item_id", item.ID,
+					"dimension", d.Name)
+				continue
+			}
+
+			min, max := d.scale()
+			if raw.Score < min || raw.Score > max {
+				slog.Warn("Dimension score out of range, clamping to valid range",
+					"item_id", item.ID,
+					"dimension", d.Name,
+					"original_score", raw.Score)
+			}
+			dimensions[d.Name] = DimensionScore{
+				Score:  clampInt(raw.Score, min, max),
+				Reason: raw.Reason,
+			}
+		}
+
+		aggregate := rubric.Aggregate(dimensions)
+		value := scale.FromPercent(aggregate)
+		results[i] = ScoredItem{
+			Item:       item,
+			Score:      int(math.Round(value)),
+			Value:      value,
+			Reason:     score.Reason,
+			Dimensions: dimensions,
+			Aggregate:  aggregate,
+		}
+	}
+
+	return results
+}
+
+// clampInt limits v to [min, max]
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,142 @@
+// Package scorer_test covers rubric scoring: the generated response schema,
+// per-dimension scores with clamping, and the weighted aggregate.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Rubric scoring", func() {
+	var (
+		ctx    context.Context
+		rubric scorer.Rubric
+		items  []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rubric = scorer.Rubric{Dimensions: []scorer.Dimension{
+			scorer.Dimension{Name: "relevance", Description: "How relevant to local events"}.WithWeight(2),
+			{Name: "specificity", Description: "Names a venue, date or time", Min: 1, Max: 5},
+			scorer.Dimension{Name: "recency", Min: 0, Max: 10}.WithWeight(1),
+		}}
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Jazz trio at the Blue Note, Friday 8pm"},
+			{ID: "2", Content: "Anyone know a good plumber?"},
+		}
+	})
+
+	It("should return per-dimension scores and a weighted aggregate", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[
+					{"item_id":"1","dimensions":{
+						"relevance":{"score":90,"reason":"live music"},
+						"specificity":{"score":5,"reason":"venue and time"},
+						"recency":{"score":8,"reason":"this week"}},
+					 "reason":"specific upcoming gig"},
+					{"item_id":"2","dimensions":{
+						"relevance":{"score":5,"reason":"not an event"},
+						"specificity":{"score":9,"reason":"out of range"},
+						"recency":{"score":0,"reason":"timeless"}},
+					 "reason":"unrelated"}]}`
+			},
+		}
+
+		s, err := scorer.NewScorer(scorer.Config{Client: client})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items, scorer.WithRubric(rubric))
+		Expect(err).ToNot(HaveOccurred())
+
+		first := results[0]
+		Expect(first.Dimensions).To(HaveLen(3))
+		Expect(first.Dimensions["specificity"]).To(Equal(scorer.DimensionScore{Score: 5, Reason: "venue and time"}))
+		// (2*0.90 + 1*1.00 + 1*0.80) / 4
+		Expect(first.Aggregate).To(BeNumerically("~", 90.0, 1e-9))
+		Expect(first.Score).To(Equal(90))
+		Expect(first.Reason).To(Equal("specific upcoming gig"))
+
+		// Out-of-range dimension scores are clamped to the dimension's scale
+		Expect(results[1].Dimensions["specificity"].Score).To(Equal(5))
+	})
+
+	It("should generate a strict schema naming every dimension", func() {
+		client := &mockScoringClient{}
+		s, err := scorer.NewScorer(scorer.Config{Client: client}.WithRubric(rubric))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, _ = s.ScoreTexts(ctx, items)
+
+		req := client.requests[0]
+		Expect(req.Messages[1].Content).To(ContainSubstring("specificity (1-5): Names a venue, date or time"))
+
+		schemaJSON, err := json.Marshal(req.ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schemaJSON)).To(ContainSubstring(`"required":["relevance","specificity","recency"]`))
+	})
+
+	It("should be accepted by a strict structured outputs server", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 100, "2": 0}))
+		server := scorertest.NewServer(fake)
+		defer server.Close()
+
+		s, err := scorer.NewScorer(scorer.NewDefaultConfig("test-key").WithBaseURL(server.BaseURL()))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items, scorer.WithRubric(rubric))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Aggregate).To(BeNumerically("~", 100.0, 1e-9))
+		Expect(results[1].Dimensions["specificity"].Score).To(Equal(1))
+		Expect(results[1].Score).To(Equal(0))
+	})
+
+	Describe("Aggregate", func() {
+		It("should weight an unset dimension as 1 and leave a zero-weighted one out", func() {
+			r := scorer.Rubric{Dimensions: []scorer.Dimension{
+				{Name: "relevance"},
+				scorer.Dimension{Name: "specificity", Min: 1, Max: 5}.WithWeight(0),
+				scorer.Dimension{Name: "recency", Max: 10}.WithWeight(3),
+			}}
+			Expect(r.Validate()).To(Succeed())
+
+			scores := map[string]scorer.DimensionScore{
+				"relevance":   {Score: 40},
+				"specificity": {Score: 5},
+				"recency":     {Score: 8},
+			}
+			// (1*0.40 + 3*0.80) / 4; specificity is scored but not weighted
+			Expect(r.Aggregate(scores)).To(BeNumerically("~", 70.0, 1e-9))
+		})
+	})
+
+	Describe("Validate", func() {
+		DescribeTable("should reject unusable rubrics",
+			func(r scorer.Rubric, message string) {
+				Expect(r.Validate()).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("no dimensions", scorer.Rubric{}, "at least one dimension"),
+			Entry("bad name", scorer.Rubric{Dimensions: []scorer.Dimension{{Name: "Item ID"}}}, "invalid rubric dimension name"),
+			Entry("duplicate", scorer.Rubric{Dimensions: []scorer.Dimension{{Name: "a"}, {Name: "a"}}}, "duplicate"),
+			Entry("empty scale", scorer.Rubric{Dimensions: []scorer.Dimension{{Name: "a", Min: 5, Max: 5}}}, "Min must be less than Max"),
+			Entry("negative weight", scorer.Rubric{Dimensions: []scorer.Dimension{scorer.Dimension{Name: "a"}.WithWeight(-1)}}, "Weight"),
+			Entry("no positive weight", scorer.Rubric{Dimensions: []scorer.Dimension{
+				scorer.Dimension{Name: "a"}.WithWeight(0),
+				scorer.Dimension{Name: "b"}.WithWeight(0),
+			}}, "positive weight"),
+		)
+
+		It("should be checked by config validation", func() {
+			cfg := scorer.NewDefaultConfig("test-key").WithRubric(scorer.Rubric{})
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("at least one dimension")))
+		})
+	})
+})
@@ -0,0 +1,227 @@
+package scorer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"text/template"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// ScoreScale is the range scores are given on. Integer scales round scores to
+// whole numbers; decimal scales keep fractional scores.
+type ScoreScale struct {
+	Min     float64 // Lowest score
+	Max     float64 // Highest score
+	Decimal bool    // Allow fractional scores (e.g. 0.0-1.0)
+}
+
+// Common score scales
+var (
+	DefaultScale  = ScoreScale{Min: 0, Max: 100}              // 0-100, used when no scale is configured
+	LikertScale   = ScoreScale{Min: 1, Max: 5}                // 1-5 Likert
+	TenPointScale = ScoreScale{Min: 0, Max: 10}               // 0-10
+	UnitScale     = ScoreScale{Min: 0, Max: 1, Decimal: true} // 0.0-1.0
+)
+
+// maxIntegerBuckets is the widest integer scale that gets one histogram bucket per score
+const maxIntegerBuckets = 20
+
+// Internal response types for scaled score parsing. Scores are read as numbers
+// so decimal scales parse; integer scales are rounded when mapped.
+type scaledScoreResponse struct {
+	Version string            `json:"version"`
+	Scores  []scaledScoreItem `json:"scores"`
+}
+
+type scaledScoreItem struct {
+	ItemID string  `json:"item_id"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// Validate checks that the scale is usable
+func (sc ScoreScale) Validate() error {
+	if math.IsNaN(sc.Min) || math.IsInf(sc.Min, 0) || math.IsNaN(sc.Max) || math.IsInf(sc.Max, 0) {
+		return errors.New("score scale bounds must be finite")
+	}
+	if sc.Min >= sc.Max {
+		return errors.New("score scale Min must be less than Max")
+	}
+	if !sc.Decimal && (sc.Min != math.Trunc(sc.Min) || sc.Max != math.Trunc(sc.Max)) {
+		return errors.New("integer score scale bounds must be whole numbers")
+	}
+	return nil
+}
+
+// String formats the scale as "min-max", with decimal scales shown to at
+// least one decimal place (e.g. "1-5", "0.0-1.0")
+func (sc ScoreScale) String() string {
+	return sc.format(sc.Min) + "-" + sc.format(sc.Max)
+}
+
+// format writes one bound of the scale
+func (sc ScoreScale) format(v float64) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if sc.Decimal && v == math.Trunc(v) {
+		s += ".0"
+	}
+	return s
+}
+
+// Clamp limits a score to the scale, rounding it on integer scales
+func (sc ScoreScale) Clamp(score float64) float64 {
+	if !sc.Decimal {
+		score = math.Round(score)
+	}
+	return math.Min(math.Max(score, sc.Min), sc.Max)
+}
+
+// FromPercent maps a 0-100 value onto the scale
+func (sc ScoreScale) FromPercent(percent float64) float64 {
+	return sc.Clamp(sc.Min + percent/100*(sc.Max-sc.Min))
+}
+
+// isDefault reports whether the scale is the 0-100 integer default
+func (sc ScoreScale) isDefault() bool {
+	return sc == DefaultScale
+}
+
+// buckets returns histogram buckets for the scale: one per score on narrow
+// integer scales, otherwise ten equal steps
+func (sc ScoreScale) buckets() []float64 {
+	if !sc.Decimal && sc.Max-sc.Min <= maxIntegerBuckets {
+		var buckets []float64
+		for v := sc.Min; v <= sc.Max; v++ {
+			buckets = append(buckets, v)
+		}
+		return buckets
+	}
+
+	buckets := make([]float64, 11)
+	for i := range buckets {
+		buckets[i] = sc.Min + float64(i)*(sc.Max-sc.Min)/10
+	}
+	return buckets
+}
+
+// schema builds the response schema for the scale. The default scale uses
+// the schema generated from scoreResponse so existing requests are unchanged.
+func (sc ScoreScale) schema() (*jsonschema.Definition, error) {
+	if sc.isDefault() {
+		return jsonschema.GenerateSchemaForType(scoreResponse{})
+	}
+
+	scoreType := jsonschema.Integer
+	if sc.Decimal {
+		scoreType = jsonschema.Number
+	}
+
+	return &jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"version": {Type: jsonschema.String},
+			"scores": {
+				Type: jsonschema.Array,
+				Items: &jsonschema.Definition{
+					Type: jsonschema.Object,
+					Properties: map[string]jsonschema.Definition{
+						"item_id": {Type: jsonschema.String},
+						"score": {
+							Type:        scoreType,
+							Description: fmt.Sprintf("Score from %s to %s", sc.format(sc.Min), sc.format(sc.Max)),
+						},
+						"reason": {Type: jsonschema.String},
+					},
+					Required:             []string{"item_id", "score", "reason"},
+					AdditionalProperties: false,
+				},
+			},
+		},
+		Required:             []string{"version", "scores"},
+		AdditionalProperties: false,
+	}, nil
+}
+
+// render fills the scale into a built-in prompt. Prompts use {{.ScaleMin}},
+// {{.ScaleMax}} and {{.ScaleKind}}, and write guideline ranges as
+// {{band 90 100}}, in percent of the scale.
+func (sc ScoreScale) render(prompt string) (string, error) {
+	tmpl, err := template.New("prompt").Funcs(template.FuncMap{"band": sc.band}).Parse(prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse prompt scale: %w", err)
+	}
+
+	kind := "integers"
+	if sc.Decimal {
+		kind = "decimal numbers"
+	}
+	data := map[string]string{
+		"ScaleMin":  sc.format(sc.Min),
+		"ScaleMax":  sc.format(sc.Max),
+		"ScaleKind": kind,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt scale: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// band formats the scores from one percentage of the scale to another, as the
+// built-in guidelines give them on 0-100. Bands start at the first score at or
+// above their lower percentage and end just below the next band, so they
+// neither overlap nor leave gaps on narrow scales.
+func (sc ScoreScale) band(from, to int) string {
+	low := sc.at(from)
+	high := sc.Max
+	if to < 100 {
+		high = sc.round(sc.at(to+1) - sc.step())
+	}
+	if high <= low {
+		return sc.format(low)
+	}
+	return sc.format(low) + "-" + sc.format(high)
+}
+
+// at returns the first score at or above a percentage of the scale
+func (sc ScoreScale) at(percent int) float64 {
+	steps := (sc.Max - sc.Min) / sc.step()
+	n := math.Ceil(float64(percent)*steps/100 - 1e-9)
+	return sc.round(sc.Min + n*sc.step())
+}
+
+// step is the gap between neighbouring scores in guideline bands: 1 on
+// integer scales and a hundredth of the range on decimal ones
+func (sc ScoreScale) step() float64 {
+	if sc.Decimal {
+		return (sc.Max - sc.Min) / 100
+	}
+	return 1
+}
+
+// round removes floating point noise from a computed score
+func (sc ScoreScale) round(v float64) float64 {
+	return math.Round(v*1e9) / 1e9
+}
+
+// scoreValue returns the exact score, falling back to Score for results from
+// Scorer implementations that do not set Value
+func (r ScoredItem) scoreValue() float64 {
+	if r.Value == 0 {
+		return float64(r.Score)
+	}
+	return r.Value
+}
+
+// resolveScale returns the configured scale or the 0-100 default
+func (c Config) resolveScale() ScoreScale {
+	if c.Scale != nil {
+		return *c.Scale
+	}
+	return DefaultScale
+}
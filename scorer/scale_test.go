@@ -0,0 +1,250 @@
+// Package scorer_test covers configurable score scales: the response schema
+// and prompt, clamping, defaults for missing items, and histogram buckets.
+package scorer_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Score scales", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Jazz trio at the Blue Note, Friday 8pm"},
+			{ID: "2", Content: "Anyone know a good plumber?"},
+			{ID: "3", Content: "Farmers market on Saturday"},
+		}
+	})
+
+	It("should describe the scale in the schema and prompt", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithScale(scorer.LikertScale))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := fake.Requests()[0]
+		schema, err := json.Marshal(req.ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).To(ContainSubstring(`"score":{"type":"integer","description":"Score from 1 to 5"}`))
+		Expect(req.Messages[0].Content).To(ContainSubstring("integers between 1 and 5, where 1 means completely irrelevant"))
+		Expect(req.Messages[1].Content).To(ContainSubstring("Score must be between 1-5"))
+		Expect(req.Messages[0].Content + req.Messages[1].Content).ToNot(ContainSubstring("100"))
+	})
+
+	DescribeTable("should fit the scoring guidelines to the scale",
+		func(scale scorer.ScoreScale, guidelines []string) {
+			fake := scorertest.NewFakeClient()
+			s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithScale(scale))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s.ScoreTexts(ctx, items)
+			Expect(err).ToNot(HaveOccurred())
+
+			prompt := fake.Requests()[0].Messages[1].Content
+			for _, line := range guidelines {
+				Expect(prompt).To(ContainSubstring("\n" + line + ": "))
+			}
+		},
+		Entry("0-100", scorer.DefaultScale, []string{"90-100", "70-89", "40-69", "1-39", "0"}),
+		Entry("1-5", scorer.LikertScale, []string{"5", "4", "3", "2", "1"}),
+		Entry("0-10", scorer.TenPointScale, []string{"9-10", "7-8", "4-6", "1-3", "0"}),
+		Entry("0.0-1.0", scorer.UnitScale, []string{"0.9-1.0", "0.7-0.89", "0.4-0.69", "0.01-0.39", "0.0"}),
+	)
+
+	It("should leave default-scale requests unchanged", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := fake.Requests()[0]
+		schema, err := json.Marshal(req.ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).ToNot(ContainSubstring("Score from"))
+		Expect(req.Messages[0].Content).To(ContainSubstring("integers between 0 and 100, where 0 means completely irrelevant"))
+		Expect(req.Messages[1].Content).To(ContainSubstring("Score must be between 0-100"))
+	})
+
+	It("should clamp to an integer scale and leave missing items unscored", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[
+					{"item_id":"1","score":7,"reason":"too high"},
+					{"item_id":"2","score":0,"reason":"too low"}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client, Scale: &scorer.LikertScale})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(results[0].Score).To(Equal(5))
+		Expect(results[0].Value).To(Equal(5.0))
+		Expect(results[1].Score).To(Equal(1))
+		Expect(results[2].Missing).To(BeTrue())
+		Expect(results[2].Score).To(Equal(0))
+	})
+
+	It("should keep fractional scores on a decimal scale", func() {
+		client := &mockScoringClient{
+			respond: func(req openai.ChatCompletionRequest) string {
+				return `{"version":"1.0","scores":[
+					{"item_id":"1","score":0.73,"reason":"good"},
+					{"item_id":"2","score":1.4,"reason":"over"},
+					{"item_id":"3","score":0.2,"reason":"weak"}]}`
+			},
+		}
+		s, err := scorer.NewScorer(scorer.Config{Client: client}.WithScale(scorer.UnitScale))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(results[0].Value).To(Equal(0.73))
+		Expect(results[0].Score).To(Equal(1))
+		Expect(results[1].Value).To(Equal(1.0))
+		Expect(results[2].Value).To(Equal(0.2))
+		Expect(results[2].Score).To(Equal(0))
+
+		schema, err := json.Marshal(client.requests[0].ResponseFormat.JSONSchema.Schema)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(schema)).To(ContainSubstring(`"type":"number","description":"Score from 0.0 to 1.0"`))
+	})
+
+	It("should map rubric aggregates onto the scale", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 100, "2": 0, "3": 50}))
+		rubric := scorer.Rubric{Dimensions: []scorer.Dimension{{Name: "relevance"}}}
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithScale(scorer.TenPointScale))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items, scorer.WithRubric(rubric))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(results[0].Aggregate).To(Equal(100.0))
+		Expect(results[0].Score).To(Equal(10))
+		Expect(results[1].Score).To(Equal(0))
+		Expect(results[2].Score).To(Equal(5))
+	})
+
+	It("should map fake scores onto the scale", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 100, "2": 0, "3": 50}))
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithScale(scorer.LikertScale))
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect([]int{results[0].Score, results[1].Score, results[2].Score}).To(Equal([]int{5, 1, 3}))
+	})
+
+	It("should record scores into a histogram with buckets for the scale", func() {
+		scale := scorer.ScoreScale{Min: 1, Max: 7}
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 100, "2": 0, "3": 50}))
+		s, err := scorer.NewIntegratedScorer(scorer.Config{Client: fake}.WithScale(scale))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		bounds, count := scoreHistogram("1-7")
+		Expect(count).To(BeNumerically(">=", 3))
+		Expect(bounds).To(Equal([]float64{1, 2, 3, 4, 5, 6, 7}))
+	})
+
+	It("should keep the default scale's histogram unlabelled", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewIntegratedScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+
+		families, err := prometheus.DefaultGatherer.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		var found bool
+		for _, family := range families {
+			if family.GetName() != "text_scorer_score_distribution" {
+				continue
+			}
+			found = true
+			Expect(family.GetHelp()).To(Equal("Distribution of scores (0-100)"))
+			Expect(family.GetMetric()).To(HaveLen(1))
+			metric := family.GetMetric()[0]
+			Expect(metric.GetLabel()).To(BeEmpty())
+			var bounds []float64
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				bounds = append(bounds, bucket.GetUpperBound())
+			}
+			Expect(bounds).To(Equal([]float64{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100}))
+		}
+		Expect(found).To(BeTrue())
+	})
+
+	DescribeTable("scale validation",
+		func(scale scorer.ScoreScale, message string) {
+			Expect(scale.Validate()).To(MatchError(ContainSubstring(message)))
+
+			_, err := scorer.NewScorer(scorer.Config{Client: scorertest.NewFakeClient()}.WithScale(scale))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("empty range", scorer.ScoreScale{Min: 5, Max: 5}, "Min must be less than Max"),
+		Entry("inverted range", scorer.ScoreScale{Min: 10, Max: 0}, "Min must be less than Max"),
+		Entry("fractional integer bounds", scorer.ScoreScale{Min: 0, Max: 2.5}, "must be whole numbers"),
+	)
+
+	It("should reject fallbacks on a different scale", func() {
+		cfg := scorer.NewDefaultConfig("test-key").WithScale(scorer.LikertScale).
+			WithFallback(scorer.NewDefaultConfig("test-key").WithScale(scorer.TenPointScale))
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("fallback 0: score scale 0-10 does not match 1-5")))
+	})
+
+	It("should format scales as min-max", func() {
+		Expect(scorer.DefaultScale.String()).To(Equal("0-100"))
+		Expect(scorer.LikertScale.String()).To(Equal("1-5"))
+		Expect(scorer.UnitScale.String()).To(Equal("0.0-1.0"))
+	})
+})
+
+// scoreHistogram reads the bucket bounds and sample count of the score
+// histogram for one non-default scale from the default registry
+func scoreHistogram(scale string) ([]float64, uint64) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "text_scorer_scaled_score_distribution" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() != "scale" || label.GetValue() != scale {
+					continue
+				}
+				var bounds []float64
+				for _, bucket := range metric.GetHistogram().GetBucket() {
+					bounds = append(bounds, bucket.GetUpperBound())
+				}
+				return bounds, metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return nil, 0
+}
@@ -7,8 +7,6 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
-
-	"github.com/sashabaranov/go-openai"
 )
 
 //go:embed prompts/*.txt
@@ -37,7 +35,7 @@ func NewScorer(cfg Config) (Scorer, error) {
 		return nil, batchPromptError
 	}
 
-	if cfg.APIKey == "" {
+	if cfg.APIKey == "" && requiresAPIKey(cfg) {
 		return nil, ErrMissingAPIKey
 	}
 
@@ -61,9 +59,24 @@ func NewScorer(cfg Config) (Scorer, error) {
 		cfg.MaxConcurrent = 1
 	}
 
+	if !isValidProvider(cfg.Provider) {
+		return nil, fmt.Errorf("unsupported provider: %s", cfg.Provider)
+	}
+
+	if cfg.Provider == ProviderAzure && cfg.AzureConfig == nil && cfg.Client == nil {
+		return nil, errors.New("azure provider requires AzureConfig")
+	}
+
+	if !isValidOutputMode(cfg.OutputMode) {
+		return nil, fmt.Errorf("unsupported output mode: %s", cfg.OutputMode)
+	}
+
 	// Set default model if not specified
 	if cfg.Model == "" {
-		cfg.Model = openai.GPT4oMini
+		cfg.Model = defaultModel(cfg.Provider)
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("model is required for provider %s", cfg.Provider)
 	}
 
 	// Set default timeout if not specified
@@ -71,16 +84,42 @@ func NewScorer(cfg Config) (Scorer, error) {
 		cfg.Timeout = 30 // 30 seconds default
 	}
 
-	prompt := batchScorePrompt
-	if cfg.PromptText != "" {
-		prompt = cfg.PromptText
+	if err := validateGeneration(cfg.Temperature, cfg.maxTemperature(), cfg.MaxOutputTokens, cfg.ReasoningEffort); err != nil {
+		return nil, err
+	}
+
+	if cfg.Rubric != nil {
+		if err := cfg.Rubric.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Scale != nil {
+		if err := cfg.Scale.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The built-in prompts describe the configured scale
+	scale := cfg.resolveScale()
+	system, err := scale.render(systemPrompt)
+	if err != nil {
+		return nil, err
+	}
+	prompt := cfg.PromptText
+	if prompt == "" {
+		prompt, err = scale.render(batchScorePrompt)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	client := openai.NewClient(cfg.APIKey)
 	return &scorer{
-		client: client,
-		config: cfg,
-		prompt: prompt,
+		client:  newClient(cfg),
+		config:  cfg,
+		prompt:  prompt,
+		system:  system,
+		metrics: NewMetricsRecorder(true).WithScale(cfg.resolveScale()),
 	}, nil
 }
 
@@ -99,36 +138,14 @@ func (s *scorer) ScoreTextsWithOptions(ctx context.Context, items []TextItem, op
 		return []ScoredItem{}, nil
 	}
 
-	// Determine max content length
-	maxContentLength := s.config.MaxContentLength
-	if maxContentLength == 0 {
-		maxContentLength = DefaultMaxContentLength
-	}
-
-	// Validate items
-	for i, item := range items {
-		if item.ID == "" {
-			return nil, fmt.Errorf("item at index %d has empty ID", i)
-		}
-		if item.Content == "" {
-			slog.Warn("Item has empty content", "item_id", item.ID, "index", i)
-		}
-
-		// Validate content length
-		contentLength := len(item.Content)
-		if contentLength > maxContentLength {
-			return nil, fmt.Errorf("item %s at index %d: %w (length: %d, max: %d)",
-				item.ID, i, ErrContentTooLong, contentLength, maxContentLength)
-		}
-		if contentLength < MinContentLength && contentLength > 0 {
-			return nil, fmt.Errorf("item %s at index %d: %w (length: %d, min: %d)",
-				item.ID, i, ErrContentTooShort, contentLength, MinContentLength)
-		}
+	if err := s.validateItems(items); err != nil {
+		return nil, err
 	}
 
 	// Apply default options
 	options := &scoringOptions{
-		model: s.config.Model,
+		model:    s.config.Model,
+		logprobs: s.config.Logprobs,
 	}
 
 	// Apply provided options
@@ -136,18 +153,62 @@ func (s *scorer) ScoreTextsWithOptions(ctx context.Context, items []TextItem, op
 		opt(options)
 	}
 
-	// Create batches
-	var batches [][]TextItem
-	for i := 0; i < len(items); i += maxBatchSize {
-		batch := items[i:min(i+maxBatchSize, len(items))]
-		batches = append(batches, batch)
+	if err := s.config.validateOptions(options); err != nil {
+		return nil, err
+	}
+	if options.rubric != nil {
+		if err := options.rubric.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if options.evidence != nil {
+		if err := options.evidence.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	batches := s.config.scoringBudget(options).split(items)
+
+	return s.scoreBatches(ctx, batches, options)
+}
+
+// validateItems checks item IDs and content lengths before any API call
+func (s *scorer) validateItems(items []TextItem) error {
+	for i, item := range items {
+		if err := s.config.validateItem(i, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateItem checks one item's ID and content length
+func (c Config) validateItem(index int, item TextItem) error {
+	// Determine max content length
+	maxContentLength := c.MaxContentLength
+	if maxContentLength == 0 {
+		maxContentLength = DefaultMaxContentLength
+	}
+
+	if item.ID == "" {
+		return fmt.Errorf("item at index %d has empty ID", index)
+	}
+	if item.Content == "" {
+		slog.Warn("Item has empty content", "item_id", item.ID, "index", index)
 	}
 
-	// Process batches based on MaxConcurrent setting
-	if s.config.MaxConcurrent <= 1 {
-		return s.processSequentially(ctx, batches, options)
+	// Validate content length
+	contentLength := len(item.Content)
+	if contentLength > maxContentLength {
+		return fmt.Errorf("item %s at index %d: %w (length: %d, max: %d)",
+			item.ID, index, ErrContentTooLong, contentLength, maxContentLength)
+	}
+	if contentLength < MinContentLength && contentLength > 0 {
+		return fmt.Errorf("item %s at index %d: %w (length: %d, min: %d)",
+			item.ID, index, ErrContentTooShort, contentLength, MinContentLength)
 	}
-	return s.processConcurrently(ctx, batches, options)
+
+	return nil
 }
 
 // GetHealth returns the current health status of the scorer
@@ -175,6 +236,7 @@ func (s *scorer) GetHealth(ctx context.Context) HealthStatus {
 		Status:  "healthy",
 		Details: map[string]interface{}{
 			"api_status":      "connected",
+			"provider":        string(s.config.providerOrDefault()),
 			"model":           s.config.Model,
 			"max_concurrent":  s.config.MaxConcurrent,
 			"circuit_breaker": s.config.EnableCircuitBreaker,
@@ -183,73 +245,41 @@ func (s *scorer) GetHealth(ctx context.Context) HealthStatus {
 	}
 }
 
-func (s *scorer) processSequentially(ctx context.Context, batches [][]TextItem, options *scoringOptions) ([]ScoredItem, error) {
-	var allResults []ScoredItem
+// scoreBatches scores batches up to Config.MaxConcurrent at a time, in order
+// when that is one, without a failed batch cancelling the others. Every item's
+// result is returned in input order; items in a batch that fails, or never
+// starts because the context is done, are Missing with a *BatchError as their
+// Err, and the error joins those batch errors.
+func (s *scorer) scoreBatches(ctx context.Context, batches [][]TextItem, options *scoringOptions) ([]ScoredItem, error) {
+	outcomes, errs := runAll(ctx, len(batches), s.config.MaxConcurrent, func(ctx context.Context, i int) ([]ScoredItem, error) {
+		return s.processBatch(ctx, batches[i], options)
+	})
+
+	var results []ScoredItem
+	failed := 0
 	for i, batch := range batches {
-		results, err := s.processBatch(ctx, batch, options)
-		if err != nil {
-			return nil, fmt.Errorf("processing batch %d: %w", i, err)
+		if errs[i] != nil {
+			slog.Warn("Batch failed, keeping other batches",
+				"batch", i,
+				"items", len(batch),
+				"error", errs[i])
+			results = append(results, failBatch(batch, i, errs[i])...)
+			failed++
+			continue
 		}
-		allResults = append(allResults, results...)
-	}
-
-	slog.Info("All items scored successfully",
-		"total_items", len(allResults),
-		"total_batches", len(batches),
-		"mode", "sequential")
-
-	return allResults, nil
-}
-
-func (s *scorer) processConcurrently(ctx context.Context, batches [][]TextItem, options *scoringOptions) ([]ScoredItem, error) {
-	type batchResult struct {
-		index   int
-		results []ScoredItem
-		err     error
-	}
-
-	// Semaphore to limit concurrent processing
-	sem := make(chan struct{}, s.config.MaxConcurrent)
-	results := make(chan batchResult, len(batches))
-
-	// Process batches concurrently
-	for i, batch := range batches {
-		go func(index int, batch []TextItem) {
-			sem <- struct{}{}        // Acquire semaphore
-			defer func() { <-sem }() // Release semaphore
-
-			batchResults, err := s.processBatch(ctx, batch, options)
-			results <- batchResult{
-				index:   index,
-				results: batchResults,
-				err:     err,
-			}
-		}(i, batch)
-	}
-
-	// Collect results in order
-	allResults := make([][]ScoredItem, len(batches))
-	for i := 0; i < len(batches); i++ {
-		result := <-results
-		if result.err != nil {
-			return nil, fmt.Errorf("processing batch %d: %w", result.index, result.err)
+		for j := range outcomes[i] {
+			outcomes[i][j].batch = i
 		}
-		allResults[result.index] = result.results
-	}
-
-	// Flatten results
-	var flatResults []ScoredItem
-	for _, batchResults := range allResults {
-		flatResults = append(flatResults, batchResults...)
+		results = append(results, outcomes[i]...)
 	}
 
-	slog.Info("All items scored successfully",
-		"total_items", len(flatResults),
+	slog.Info("Finished scoring batches",
+		"total_items", len(results),
 		"total_batches", len(batches),
-		"mode", "concurrent",
+		"failed_batches", failed,
 		"max_concurrent", s.config.MaxConcurrent)
 
-	return flatResults, nil
+	return results, batchErrors(results)
 }
 
 // Helper function for min
@@ -0,0 +1,331 @@
+package scorertest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+)
+
+// cassetteVersion is the on-disk format version written to cassette files
+const cassetteVersion = 1
+
+// ErrUnrecordedRequest is returned in strict mode for requests with no recording
+var ErrUnrecordedRequest = errors.New("request not recorded in cassette")
+
+// CassetteMode selects whether a cassette replays or re-records interactions
+type CassetteMode string
+
+const (
+	// CassetteReplay replays recorded interactions. Unrecorded requests are sent
+	// to the wrapped client and recorded, unless the cassette is strict.
+	CassetteReplay CassetteMode = "replay"
+	// CassetteRecord sends every request to the wrapped client and replaces
+	// any earlier recordings for it.
+	CassetteRecord CassetteMode = "record"
+)
+
+// Interaction is one recorded request and its outcome
+type Interaction struct {
+	Hash     string                         `json:"hash"`
+	Request  json.RawMessage                `json:"request"`
+	Response *openai.ChatCompletionResponse `json:"response,omitempty"`
+	Error    *RecordedError                 `json:"error,omitempty"`
+}
+
+// RecordedError is an API error captured in a cassette
+type RecordedError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`
+}
+
+// cassetteFile is the on-disk cassette layout
+type cassetteFile struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Cassette is a scorer.OpenAIClient decorator that records requests and
+// responses to a file and replays them later. Requests are matched by a hash
+// of the model, messages and response schema, so regression tests of prompts
+// can run offline against real model output captured once.
+//
+// Identical requests recorded several times replay in recorded order, which
+// lets a cassette capture a rate-limit error followed by a successful retry.
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	client       scorer.OpenAIClient
+	mode         CassetteMode
+	strict       bool
+	interactions []Interaction
+	loaded       int // Interactions read from disk; only these are replayed
+	replayed     map[string]int
+	rerecorded   map[string]bool
+}
+
+// CassetteOption configures a Cassette
+type CassetteOption func(*Cassette)
+
+// WithCassetteMode sets the cassette mode (default: CassetteReplay)
+func WithCassetteMode(mode CassetteMode) CassetteOption {
+	return func(c *Cassette) {
+		c.mode = mode
+	}
+}
+
+// WithStrict makes unrecorded requests fail with ErrUnrecordedRequest instead
+// of reaching the wrapped client. Use it in CI to keep tests offline.
+func WithStrict() CassetteOption {
+	return func(c *Cassette) {
+		c.strict = true
+	}
+}
+
+// NewCassette opens the cassette at path, loading any existing recordings.
+// Client is the real client used for recording; it may be nil for replay-only use.
+func NewCassette(path string, client scorer.OpenAIClient, opts ...CassetteOption) (*Cassette, error) {
+	c := &Cassette{
+		path:       path,
+		client:     client,
+		mode:       CassetteReplay,
+		replayed:   make(map[string]int),
+		rerecorded: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	switch c.mode {
+	case CassetteReplay, CassetteRecord:
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode: %s", c.mode)
+	}
+	if c.mode == CassetteRecord && client == nil {
+		return nil, errors.New("record mode requires a client")
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return c, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if file.Version != cassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s", file.Version, path)
+	}
+	c.interactions = file.Interactions
+	c.loaded = len(file.Interactions)
+
+	return c, nil
+}
+
+// CreateChatCompletion implements scorer.OpenAIClient
+func (c *Cassette) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	hash, err := RequestHash(req)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+
+	if c.mode == CassetteReplay {
+		if interaction, ok := c.next(hash); ok {
+			return interaction.replay()
+		}
+		if c.strict {
+			return openai.ChatCompletionResponse{}, fmt.Errorf("%w: model %s, hash %s", ErrUnrecordedRequest, req.Model, hash)
+		}
+		if c.client == nil {
+			return openai.ChatCompletionResponse{}, fmt.Errorf("%w and no client to record with: hash %s", ErrUnrecordedRequest, hash)
+		}
+	}
+
+	resp, callErr := c.client.CreateChatCompletion(ctx, req)
+
+	interaction := Interaction{Hash: hash}
+	var apiErr *openai.APIError
+	switch {
+	case callErr == nil:
+		interaction.Response = &resp
+	case errors.As(callErr, &apiErr):
+		interaction.Error = &RecordedError{
+			Status:  apiErr.HTTPStatusCode,
+			Message: apiErr.Message,
+			Type:    apiErr.Type,
+		}
+	default:
+		// Transport failures and cancellations are not part of the model's behaviour
+		return resp, callErr
+	}
+
+	if interaction.Request, err = json.Marshal(req); err != nil {
+		return resp, fmt.Errorf("failed to encode request for cassette: %w", err)
+	}
+	if err := c.record(interaction); err != nil {
+		return resp, err
+	}
+
+	return resp, callErr
+}
+
+// Interactions returns a copy of the recorded interactions
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// next returns the next loaded recording for a hash, repeating the last one
+// once all have been replayed. Interactions recorded during this session are
+// not replayed, so repeated requests while recording reach the real client.
+func (c *Cassette) next(hash string) (Interaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matches []Interaction
+	for _, interaction := range c.interactions[:c.loaded] {
+		if interaction.Hash == hash {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		return Interaction{}, false
+	}
+
+	i := c.replayed[hash]
+	c.replayed[hash] = i + 1
+	if i >= len(matches) {
+		i = len(matches) - 1
+	}
+	return matches[i], true
+}
+
+// record stores an interaction and writes the cassette to disk. In record
+// mode the first new recording of a hash replaces the old ones.
+func (c *Cassette) record(interaction Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.mode == CassetteRecord && !c.rerecorded[interaction.Hash] {
+		kept := c.interactions[:0]
+		for _, existing := range c.interactions {
+			if existing.Hash != interaction.Hash {
+				kept = append(kept, existing)
+			}
+		}
+		c.interactions = kept
+		c.rerecorded[interaction.Hash] = true
+	}
+	c.interactions = append(c.interactions, interaction)
+
+	return c.save()
+}
+
+// save writes the cassette atomically so an interrupted test run never
+// leaves a half-written file behind
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(cassetteFile{
+		Version:      cassetteVersion,
+		Interactions: c.interactions,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// replay returns the recorded outcome
+func (i Interaction) replay() (openai.ChatCompletionResponse, error) {
+	if i.Error != nil {
+		return openai.ChatCompletionResponse{}, &openai.APIError{
+			HTTPStatusCode: i.Error.Status,
+			Message:        i.Error.Message,
+			Type:           i.Error.Type,
+		}
+	}
+	if i.Response == nil {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("cassette interaction %s has no response", i.Hash)
+	}
+	return *i.Response, nil
+}
+
+// RequestHash returns the normalized hash used to match requests. It covers
+// the model, the role and content of each message with line endings and
+// surrounding whitespace normalized, and the response schema. Generation
+// parameters are left out so tuning them does not invalidate recordings.
+func RequestHash(req openai.ChatCompletionRequest) (string, error) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	normalized := struct {
+		Model    string          `json:"model"`
+		Messages []message       `json:"messages"`
+		Schema   json.RawMessage `json:"schema,omitempty"`
+	}{Model: req.Model}
+
+	for _, msg := range req.Messages {
+		content := msg.Content
+		for _, part := range msg.MultiContent {
+			content += part.Text
+		}
+		content = strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+		normalized.Messages = append(normalized.Messages, message{Role: msg.Role, Content: content})
+	}
+
+	if req.ResponseFormat != nil && req.ResponseFormat.JSONSchema != nil && req.ResponseFormat.JSONSchema.Schema != nil {
+		schema, err := canonicalJSON(req.ResponseFormat.JSONSchema.Schema)
+		if err != nil {
+			return "", fmt.Errorf("failed to normalize response schema: %w", err)
+		}
+		normalized.Schema = schema
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSON re-encodes a value through a generic map so object keys are sorted
+func canonicalJSON(v interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
@@ -0,0 +1,154 @@
+package scorertest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Cassette", func() {
+	var (
+		ctx   context.Context
+		path  string
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "cassettes", "scoring.json")
+		items = []scorer.TextItem{
+			{ID: "1", Content: "Quiz night at the Red Lion", Metadata: map[string]interface{}{"city": "Leeds", "source": "forum"}},
+			{ID: "2", Content: "My cat is asleep"},
+		}
+	})
+
+	score := func(client scorer.OpenAIClient, opts ...scorer.ScoringOption) ([]scorer.ScoredItem, error) {
+		s, err := scorer.NewScorer(scorer.Config{Client: client})
+		Expect(err).ToNot(HaveOccurred())
+		return s.ScoreTexts(ctx, items, opts...)
+	}
+
+	It("should replay recorded responses offline", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 88, "2": 3}))
+		recorder, err := scorertest.NewCassette(path, fake)
+		Expect(err).ToNot(HaveOccurred())
+
+		recorded, err := score(recorder)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(path).To(BeAnExistingFile())
+
+		player, err := scorertest.NewCassette(path, nil, scorertest.WithStrict())
+		Expect(err).ToNot(HaveOccurred())
+
+		replayed, err := score(player)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(replayed).To(Equal(recorded))
+		Expect(fake.Calls()).To(Equal(1))
+	})
+
+	It("should fail on unrecorded requests in strict mode", func() {
+		recorder, err := scorertest.NewCassette(path, scorertest.NewFakeClient())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = score(recorder)
+		Expect(err).ToNot(HaveOccurred())
+
+		player, err := scorertest.NewCassette(path, scorertest.NewFakeClient(), scorertest.WithStrict())
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = score(player, scorer.WithPromptTemplate("Rate these for a new audience: %s"))
+		Expect(errors.Is(err, scorertest.ErrUnrecordedRequest)).To(BeTrue())
+	})
+
+	It("should replay API errors in recorded order", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 429, Message: "slow down"})
+		recorder, err := scorertest.NewCassette(path, fake)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = score(recorder)
+		Expect(err).To(HaveOccurred())
+		_, err = score(recorder)
+		Expect(err).ToNot(HaveOccurred())
+
+		player, err := scorertest.NewCassette(path, nil, scorertest.WithStrict())
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = score(player)
+		var apiErr *openai.APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.HTTPStatusCode).To(Equal(429))
+		Expect(apiErr.Message).To(Equal("slow down"))
+
+		_, err = score(player)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should replace recordings in record mode", func() {
+		first, err := scorertest.NewCassette(path, scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 10})))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = score(first)
+		Expect(err).ToNot(HaveOccurred())
+
+		rerecord, err := scorertest.NewCassette(path,
+			scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 70})),
+			scorertest.WithCassetteMode(scorertest.CassetteRecord))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = score(rerecord)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rerecord.Interactions()).To(HaveLen(1))
+
+		player, err := scorertest.NewCassette(path, nil, scorertest.WithStrict())
+		Expect(err).ToNot(HaveOccurred())
+		results, err := score(player)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(70))
+	})
+
+	It("should reject a corrupt cassette file", func() {
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte("{not json"), 0o644)).To(Succeed())
+
+		_, err := scorertest.NewCassette(path, nil)
+		Expect(err).To(MatchError(ContainSubstring("failed to parse cassette")))
+	})
+
+	Describe("RequestHash", func() {
+		request := func(content string) openai.ChatCompletionRequest {
+			return openai.ChatCompletionRequest{
+				Model:    openai.GPT4oMini,
+				Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: content}},
+			}
+		}
+
+		It("should ignore line endings, surrounding whitespace and generation parameters", func() {
+			a := request("Item 1 (ID: x):\nhello\n")
+			b := request("Item 1 (ID: x):\r\nhello")
+			b.Temperature = 0.7
+
+			hashA, err := scorertest.RequestHash(a)
+			Expect(err).ToNot(HaveOccurred())
+			hashB, err := scorertest.RequestHash(b)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hashA).To(Equal(hashB))
+		})
+
+		It("should distinguish models and content", func() {
+			a, _ := scorertest.RequestHash(request("hello"))
+			b, _ := scorertest.RequestHash(request("goodbye"))
+			other := request("hello")
+			other.Model = openai.GPT4o
+			c, _ := scorertest.RequestHash(other)
+
+			Expect(a).ToNot(Equal(b))
+			Expect(a).ToNot(Equal(c))
+		})
+	})
+})
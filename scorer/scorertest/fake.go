@@ -0,0 +1,990 @@
+// Package scorertest provides test doubles for code built on the scorer package.
+//
+// FakeClient implements scorer.OpenAIClient and answers scoring requests by
+// reading the items out of the prompt, so tests run through the real batching,
+// prompt formatting and score mapping code instead of a hand-written mock:
+//
+//	fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 90}))
+//	s, _ := scorer.NewScorer(scorer.Config{Client: fake})
+//	results, _ := s.ScoreTexts(ctx, items)
+//
+// Faults such as HTTP errors, malformed JSON, missing items, refusals and
+// truncated responses can be scripted per call with FailNext or for every call with FailAlways.
+package scorertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// DefaultScore is the score given to items without a scripted score or score function
+const DefaultScore = 50
+
+// Item is a text item parsed out of a scoring request
+type Item struct {
+	Index   int    // Position in the batch, starting at 1
+	ID      string // Item ID as written in the prompt
+	Content string // Item content, including any metadata line
+}
+
+// ScoreFunc decides the score and reason for one item
+type ScoreFunc func(item Item) (score int, reason string)
+
+// Fault describes a failure to inject into a response
+type Fault struct {
+	Status        int      // Return an *openai.APIError with this HTTP status code
+	Message       string   // Error message for Status (default: a generic message)
+	Err           error    // Return this error as-is
+	MalformedJSON bool     // Return content that is not valid JSON
+	MissingIDs    []string // Omit these items from the scores
+	Truncate      bool     // Cut the JSON short and report finish_reason "length"
+	Refusal       string   // Refuse with this message instead of answering
+	NoChoices     bool     // Return a response with an empty choices list
+}
+
+// FakeClient is a scriptable scorer.OpenAIClient. It is safe for concurrent use.
+type FakeClient struct {
+	mu        sync.Mutex
+	requests  []openai.ChatCompletionRequest
+	scores    map[string]int
+	reasons   map[string]string
+	labels    map[string][]string
+	extracted map[string]any
+	entities  map[string]any
+	evidence  map[string][]string
+	refusals  map[string]string
+	maxItems  int
+	scoreFunc ScoreFunc
+	latency   time.Duration
+	queued    []Fault
+	always    *Fault
+}
+
+// FakeOption configures a FakeClient
+type FakeOption func(*FakeClient)
+
+// WithScores sets fixed scores by item ID on a 0-100 scale. Requests on
+// another score scale get the score mapped proportionally onto it.
+func WithScores(scores map[string]int) FakeOption {
+	return func(f *FakeClient) {
+		for id, score := range scores {
+			f.scores[id] = score
+		}
+	}
+}
+
+// WithReasons sets fixed reasons by item ID
+func WithReasons(reasons map[string]string) FakeOption {
+	return func(f *FakeClient) {
+		for id, reason := range reasons {
+			f.reasons[id] = reason
+		}
+	}
+}
+
+// WithLabels sets the labels returned by ID for classification requests.
+// Items without scripted labels get the first label in the set.
+func WithLabels(labels map[string][]string) FakeOption {
+	return func(f *FakeClient) {
+		for id, itemLabels := range labels {
+			f.labels[id] = append([]string(nil), itemLabels...)
+		}
+	}
+}
+
+// WithExtractions sets the data returned by ID for extraction requests. Each
+// value is marshalled as JSON, so structs and maps both work. Items without
+// scripted data get zero values for every field in the schema.
+func WithExtractions(data map[string]any) FakeOption {
+	return func(f *FakeClient) {
+		for id, value := range data {
+			f.extracted[id] = value
+		}
+	}
+}
+
+// WithEntities sets the entities returned by ID when scoring requests ask
+// for them. Each value is marshalled as JSON in the raw response shape, e.g.
+// map[string]any{"venues": []string{"The Blue Note"}}. Items without scripted
+// entities get empty lists.
+func WithEntities(entities map[string]any) FakeOption {
+	return func(f *FakeClient) {
+		for id, value := range entities {
+			f.entities[id] = value
+		}
+	}
+}
+
+// WithEvidence sets the evidence quotes returned by ID when scoring requests
+// ask for them. Items without scripted quotes get the first line of their
+// content as a single quote, which verifies.
+func WithEvidence(quotes map[string][]string) FakeOption {
+	return func(f *FakeClient) {
+		for id, itemQuotes := range quotes {
+			f.evidence[id] = append([]string(nil), itemQuotes...)
+		}
+	}
+}
+
+// WithRefusals refuses, with the given message, any request that contains
+// one of these item IDs
+func WithRefusals(refusals map[string]string) FakeOption {
+	return func(f *FakeClient) {
+		for id, message := range refusals {
+			f.refusals[id] = message
+		}
+	}
+}
+
+// WithOutputLimit truncates the response to any request with more than
+// items items, as if the answer ran past the output token limit
+func WithOutputLimit(items int) FakeOption {
+	return func(f *FakeClient) {
+		f.maxItems = items
+	}
+}
+
+// WithScoreFunc scores items without a fixed score by calling fn
+func WithScoreFunc(fn ScoreFunc) FakeOption {
+	return func(f *FakeClient) {
+		f.scoreFunc = fn
+	}
+}
+
+// WithLatency delays every response, honouring context cancellation
+func WithLatency(latency time.Duration) FakeOption {
+	return func(f *FakeClient) {
+		f.latency = latency
+	}
+}
+
+// NewFakeClient creates a fake client. Items without a scripted score get DefaultScore.
+func NewFakeClient(opts ...FakeOption) *FakeClient {
+	f := &FakeClient{
+		scores:    make(map[string]int),
+		reasons:   make(map[string]string),
+		labels:    make(map[string][]string),
+		extracted: make(map[string]any),
+		entities:  make(map[string]any),
+		evidence:  make(map[string][]string),
+		refusals:  make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// FailNext queues faults that are applied to the next calls, one per call
+func (f *FakeClient) FailNext(faults ...Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queued = append(f.queued, faults...)
+}
+
+// FailAlways applies a fault to every call once the queued faults are used up
+func (f *FakeClient) FailAlways(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.always = &fault
+}
+
+// Recover clears queued and persistent faults
+func (f *FakeClient) Recover() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queued = nil
+	f.always = nil
+}
+
+// Requests returns a copy of every request received
+func (f *FakeClient) Requests() []openai.ChatCompletionRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]openai.ChatCompletionRequest(nil), f.requests...)
+}
+
+// Calls returns the number of requests received
+func (f *FakeClient) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+// CreateChatCompletion implements scorer.OpenAIClient
+func (f *FakeClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	fault := f.record(req)
+
+	if f.latency > 0 {
+		select {
+		case <-ctx.Done():
+			return openai.ChatCompletionResponse{}, ctx.Err()
+		case <-time.After(f.latency):
+		}
+	}
+
+	if fault.Err != nil {
+		return openai.ChatCompletionResponse{}, fault.Err
+	}
+	if fault.Status != 0 {
+		message := fault.Message
+		if message == "" {
+			message = fmt.Sprintf("fake error with status %d", fault.Status)
+		}
+		return openai.ChatCompletionResponse{}, &openai.APIError{
+			HTTPStatusCode: fault.Status,
+			Message:        message,
+		}
+	}
+
+	if fault.NoChoices {
+		return openai.ChatCompletionResponse{
+			ID:      fmt.Sprintf("fake-%d", f.Calls()),
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   req.Model,
+			Choices: []openai.ChatCompletionChoice{},
+		}, nil
+	}
+
+	items := ParseItems(req)
+	refusal := fault.Refusal
+	for _, item := range items {
+		if message, ok := f.refusals[item.ID]; ok && refusal == "" {
+			refusal = message
+		}
+	}
+	if refusal != "" {
+		return openai.ChatCompletionResponse{
+			ID:      fmt.Sprintf("fake-%d", f.Calls()),
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   req.Model,
+			Choices: []openai.ChatCompletionChoice{
+				{
+					Index: 0,
+					Message: openai.ChatCompletionMessage{
+						Role:    openai.ChatMessageRoleAssistant,
+						Refusal: refusal,
+					},
+					FinishReason: openai.FinishReasonStop,
+				},
+			},
+			Usage: EstimateUsage(req, refusal),
+		}, nil
+	}
+
+	var content string
+	var err error
+	if dimensions := rubricDimensions(req); len(dimensions) > 0 {
+		content, err = f.respondRubric(items, fault.MissingIDs, dimensions)
+	} else if labelSet, confidence, ok := classificationLabels(req); ok {
+		content, err = f.respondClassification(items, fault.MissingIDs, labelSet, confidence)
+	} else if data, ok := extractionSchema(req); ok {
+		content, err = f.respondExtraction(items, fault.MissingIDs, data)
+	} else if hasSchemaProperty(req, "ranking") {
+		content, err = f.respondRanking(items, fault.MissingIDs)
+	} else if scale, ok := scoreScale(req); ok {
+		content, err = f.respondScaled(items, fault.MissingIDs, scale)
+	} else {
+		content, err = f.Respond(items, fault.MissingIDs)
+	}
+	if err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	if schema, ok := scoreProperty(req, "entities"); ok {
+		content, err = attachScoreProperty(content, "entities", func(id string) any {
+			return f.entitiesFor(id, schema)
+		})
+		if err != nil {
+			return openai.ChatCompletionResponse{}, err
+		}
+	}
+	if _, ok := scoreProperty(req, "evidence"); ok {
+		content, err = attachScoreProperty(content, "evidence", func(id string) any {
+			return f.evidenceFor(id, items)
+		})
+		if err != nil {
+			return openai.ChatCompletionResponse{}, err
+		}
+	}
+
+	finishReason := openai.FinishReasonStop
+	switch {
+	case fault.MalformedJSON:
+		content = `{"version":"1.0","scores":[{"item_id":` + "\n" + `not json`
+	case fault.Truncate, f.maxItems > 0 && len(items) > f.maxItems:
+		content = content[:len(content)/2]
+		finishReason = openai.FinishReasonLength
+	}
+
+	var logprobs *openai.LogProbs
+	if req.LogProbs {
+		logprobs = FakeLogprobs(content)
+	}
+
+	return openai.ChatCompletionResponse{
+		ID:      fmt.Sprintf("fake-%d", f.Calls()),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Index: 0,
+				Message: openai.ChatCompletionMessage{
+					Role:    openai.ChatMessageRoleAssistant,
+					Content: content,
+				},
+				FinishReason: finishReason,
+				LogProbs:     logprobs,
+			},
+		},
+		Usage: EstimateUsage(req, content),
+	}, nil
+}
+
+// record stores the request and pops the fault for this call
+func (f *FakeClient) record(req openai.ChatCompletionRequest) Fault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, req)
+
+	if len(f.queued) > 0 {
+		fault := f.queued[0]
+		f.queued = f.queued[1:]
+		return fault
+	}
+	if f.always != nil {
+		return *f.always
+	}
+	return Fault{}
+}
+
+// Respond builds the JSON content scoring the given items, leaving out any
+// items in missing
+func (f *FakeClient) Respond(items []Item, missing []string) (string, error) {
+	skip := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		skip[id] = true
+	}
+
+	type score struct {
+		ItemID string `json:"item_id"`
+		Score  int    `json:"score"`
+		Reason string `json:"reason"`
+	}
+	response := struct {
+		Version string  `json:"version"`
+		Scores  []score `json:"scores"`
+	}{Version: "1.0", Scores: []score{}}
+
+	for _, item := range items {
+		if skip[item.ID] {
+			continue
+		}
+		value, reason := f.score(item)
+		response.Scores = append(response.Scores, score{ItemID: item.ID, Score: value, Reason: reason})
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fake response: %w", err)
+	}
+	return string(content), nil
+}
+
+// scaleBounds is a score scale as read from the request schema
+type scaleBounds struct {
+	min, max float64
+	decimal  bool
+}
+
+// scoreScale reads a non-default score scale from the score description in
+// the response schema
+func scoreScale(req openai.ChatCompletionRequest) (scaleBounds, bool) {
+	schema, ok := requestSchema(req)
+	if !ok {
+		return scaleBounds{}, false
+	}
+
+	scores, ok := schema.Properties["scores"]
+	if !ok || scores.Items == nil {
+		return scaleBounds{}, false
+	}
+	score, ok := scores.Items.Properties["score"]
+	if !ok {
+		return scaleBounds{}, false
+	}
+
+	var scale scaleBounds
+	if n, _ := fmt.Sscanf(score.Description, "Score from %g to %g", &scale.min, &scale.max); n != 2 {
+		return scaleBounds{}, false
+	}
+	scale.decimal = score.Type == jsonschema.Number
+	return scale, true
+}
+
+// respondScaled builds score JSON content with each item's 0-100 score mapped
+// proportionally onto the requested scale
+func (f *FakeClient) respondScaled(items []Item, missing []string, scale scaleBounds) (string, error) {
+	skip := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		skip[id] = true
+	}
+
+	type score struct {
+		ItemID string  `json:"item_id"`
+		Score  float64 `json:"score"`
+		Reason string  `json:"reason"`
+	}
+	response := struct {
+		Version string  `json:"version"`
+		Scores  []score `json:"scores"`
+	}{Version: "1.0", Scores: []score{}}
+
+	for _, item := range items {
+		if skip[item.ID] {
+			continue
+		}
+		value, reason := f.score(item)
+
+		scaled := scale.min + float64(value)*(scale.max-scale.min)/100
+		if !scale.decimal {
+			scaled = math.Round(scaled)
+		}
+		response.Scores = append(response.Scores, score{ItemID: item.ID, Score: scaled, Reason: reason})
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fake response: %w", err)
+	}
+	return string(content), nil
+}
+
+// respondRanking orders items by their scripted scores, highest first, with
+// ties kept in prompt order
+func (f *FakeClient) respondRanking(items []Item, missing []string) (string, error) {
+	skip := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		skip[id] = true
+	}
+
+	type ranked struct {
+		id    string
+		score int
+	}
+	var order []ranked
+	for _, item := range items {
+		if skip[item.ID] {
+			continue
+		}
+		value, _ := f.score(item)
+		order = append(order, ranked{id: item.ID, score: value})
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return order[a].score > order[b].score
+	})
+
+	response := struct {
+		Ranking []string `json:"ranking"`
+		Reason  string   `json:"reason"`
+	}{Ranking: []string{}, Reason: "fake ranking by score"}
+	for _, r := range order {
+		response.Ranking = append(response.Ranking, r.id)
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fake response: %w", err)
+	}
+	return string(content), nil
+}
+
+// classificationLabels reads the label enum from a classification response
+// schema and whether it asks for confidences
+func classificationLabels(req openai.ChatCompletionRequest) ([]string, bool, bool) {
+	schema, ok := requestSchema(req)
+	if !ok {
+		return nil, false, false
+	}
+
+	classifications, ok := schema.Properties["classifications"]
+	if !ok || classifications.Items == nil {
+		return nil, false, false
+	}
+	labels, ok := classifications.Items.Properties["labels"]
+	if !ok || labels.Items == nil {
+		return nil, false, false
+	}
+
+	_, confidence := labels.Items.Properties["confidence"]
+	return labels.Items.Properties["label"].Enum, confidence, true
+}
+
+// respondClassification builds classification JSON content from the scripted
+// labels, giving unscripted items the first label in the set
+func (f *FakeClient) respondClassification(items []Item, missing []string, labelSet []string, confidence bool) (string, error) {
+	skip := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		skip[id] = true
+	}
+
+	type label struct {
+		Label      string   `json:"label"`
+		Reason     string   `json:"reason"`
+		Confidence *float64 `json:"confidence,omitempty"`
+	}
+	type classification struct {
+		ItemID string  `json:"item_id"`
+		Labels []label `json:"labels"`
+	}
+	response := struct {
+		Version         string           `json:"version"`
+		Classifications []classification `json:"classifications"`
+	}{Version: "1.0", Classifications: []classification{}}
+
+	for _, item := range items {
+		if skip[item.ID] {
+			continue
+		}
+
+		f.mu.Lock()
+		names, scripted := f.labels[item.ID]
+		f.mu.Unlock()
+		if !scripted && len(labelSet) > 0 {
+			names = labelSet[:1]
+		}
+
+		classified := classification{ItemID: item.ID, Labels: []label{}}
+		for _, name := range names {
+			l := label{Label: name, Reason: fmt.Sprintf("fake label %s for item %s", name, item.ID)}
+			if confidence {
+				value := 1.0
+				l.Confidence = &value
+			}
+			classified.Labels = append(classified.Labels, l)
+		}
+		response.Classifications = append(response.Classifications, classified)
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fake response: %w", err)
+	}
+	return string(content), nil
+}
+
+// extractionSchema reads the per-item data schema from an extraction response schema
+func extractionSchema(req openai.ChatCompletionRequest) (jsonschema.Definition, bool) {
+	schema, ok := requestSchema(req)
+	if !ok {
+		return jsonschema.Definition{}, false
+	}
+
+	extractions, ok := schema.Properties["extractions"]
+	if !ok || extractions.Items == nil {
+		return jsonschema.Definition{}, false
+	}
+	data, ok := extractions.Items.Properties["data"]
+	return data, ok
+}
+
+// respondExtraction builds extraction JSON content from the scripted data,
+// giving unscripted items zero values for the data schema
+func (f *FakeClient) respondExtraction(items []Item, missing []string, data jsonschema.Definition) (string, error) {
+	skip := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		skip[id] = true
+	}
+
+	type extraction struct {
+		ItemID string `json:"item_id"`
+		Data   any    `json:"data"`
+	}
+	response := struct {
+		Version     string       `json:"version"`
+		Extractions []extraction `json:"extractions"`
+	}{Version: "1.0", Extractions: []extraction{}}
+
+	for _, item := range items {
+		if skip[item.ID] {
+			continue
+		}
+
+		f.mu.Lock()
+		value, scripted := f.extracted[item.ID]
+		f.mu.Unlock()
+		if !scripted {
+			value = zeroValue(data)
+		}
+		response.Extractions = append(response.Extractions, extraction{ItemID: item.ID, Data: value})
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fake response: %w", err)
+	}
+	return string(content), nil
+}
+
+// zeroValue builds the zero JSON value for a schema
+func zeroValue(schema jsonschema.Definition) any {
+	switch schema.Type {
+	case jsonschema.Object:
+		obj := make(map[string]any, len(schema.Properties))
+		for name, property := range schema.Properties {
+			obj[name] = zeroValue(property)
+		}
+		return obj
+	case jsonschema.Array:
+		return []any{}
+	case jsonschema.String:
+		return ""
+	case jsonschema.Integer, jsonschema.Number:
+		return 0
+	case jsonschema.Boolean:
+		return false
+	default:
+		return nil
+	}
+}
+
+// scoreProperty reads a property added to each score in a scoring response
+// schema, such as entities or evidence
+func scoreProperty(req openai.ChatCompletionRequest, name string) (jsonschema.Definition, bool) {
+	schema, ok := requestSchema(req)
+	if !ok {
+		return jsonschema.Definition{}, false
+	}
+
+	scores, ok := schema.Properties["scores"]
+	if !ok || scores.Items == nil {
+		return jsonschema.Definition{}, false
+	}
+	property, ok := scores.Items.Properties[name]
+	return property, ok
+}
+
+// attachScoreProperty sets a property on every score in the content to the
+// value returned for its item ID
+func attachScoreProperty(content, name string, value func(id string) any) (string, error) {
+	var response map[string]any
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return "", fmt.Errorf("failed to parse fake response: %w", err)
+	}
+
+	scores, _ := response["scores"].([]any)
+	for _, score := range scores {
+		obj, ok := score.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := obj["item_id"].(string)
+		obj[name] = value(id)
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fake response: %w", err)
+	}
+	return string(data), nil
+}
+
+// entitiesFor returns the scripted entities for an item, or empty entities for the schema
+func (f *FakeClient) entitiesFor(id string, schema jsonschema.Definition) any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if value, scripted := f.entities[id]; scripted {
+		return value
+	}
+	return zeroValue(schema)
+}
+
+// evidenceFor returns the scripted quotes for an item, or the first line of
+// its content as a single quote
+func (f *FakeClient) evidenceFor(id string, items []Item) []string {
+	f.mu.Lock()
+	quotes, scripted := f.evidence[id]
+	f.mu.Unlock()
+	if scripted {
+		return quotes
+	}
+
+	for _, item := range items {
+		if item.ID == id {
+			line, _, _ := strings.Cut(item.Content, "\n")
+			return []string{strings.TrimSpace(line)}
+		}
+	}
+	return []string{}
+}
+
+// requestSchema decodes the request's response schema, or returns false when
+// the request carries none
+func requestSchema(req openai.ChatCompletionRequest) (jsonschema.Definition, bool) {
+	var schema jsonschema.Definition
+	if req.ResponseFormat == nil || req.ResponseFormat.JSONSchema == nil || req.ResponseFormat.JSONSchema.Schema == nil {
+		return schema, false
+	}
+
+	data, err := json.Marshal(req.ResponseFormat.JSONSchema.Schema)
+	if err != nil {
+		return schema, false
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return schema, false
+	}
+	return schema, true
+}
+
+// hasSchemaProperty reports whether the response schema has a top-level property
+func hasSchemaProperty(req openai.ChatCompletionRequest, name string) bool {
+	schema, ok := requestSchema(req)
+	if !ok {
+		return false
+	}
+	_, found := schema.Properties[name]
+	return found
+}
+
+// rubricScale is a rubric dimension's scale as read from the request schema
+type rubricScale struct {
+	min, max int
+}
+
+// rubricDimensions reads the rubric dimensions and their scales from a
+// rubric response schema, returning nil for single-score requests
+func rubricDimensions(req openai.ChatCompletionRequest) map[string]rubricScale {
+	schema, ok := requestSchema(req)
+	if !ok {
+		return nil
+	}
+
+	scores, ok := schema.Properties["scores"]
+	if !ok || scores.Items == nil {
+		return nil
+	}
+	dimensions, ok := scores.Items.Properties["dimensions"]
+	if !ok {
+		return nil
+	}
+
+	scales := make(map[string]rubricScale, len(dimensions.Properties))
+	for name, dimension := range dimensions.Properties {
+		scale := rubricScale{min: 0, max: 100}
+		fmt.Sscanf(dimension.Description, "Integer score from %d to %d", &scale.min, &scale.max)
+		scales[name] = scale
+	}
+	return scales
+}
+
+// respondRubric builds rubric JSON content. Each dimension gets the item's
+// 0-100 score mapped proportionally onto the dimension's scale.
+func (f *FakeClient) respondRubric(items []Item, missing []string, dimensions map[string]rubricScale) (string, error) {
+	skip := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		skip[id] = true
+	}
+
+	type dimensionScore struct {
+		Score  int    `json:"score"`
+		Reason string `json:"reason"`
+	}
+	type score struct {
+		ItemID     string                    `json:"item_id"`
+		Dimensions map[string]dimensionScore `json:"dimensions"`
+		Reason     string                    `json:"reason"`
+	}
+	response := struct {
+		Version string  `json:"version"`
+		Scores  []score `json:"scores"`
+	}{Version: "1.0", Scores: []score{}}
+
+	for _, item := range items {
+		if skip[item.ID] {
+			continue
+		}
+		value, reason := f.score(item)
+
+		scored := score{ItemID: item.ID, Dimensions: make(map[string]dimensionScore, len(dimensions)), Reason: reason}
+		for name, scale := range dimensions {
+			scored.Dimensions[name] = dimensionScore{
+				Score:  scale.min + value*(scale.max-scale.min)/100,
+				Reason: fmt.Sprintf("fake %s score for item %s", name, item.ID),
+			}
+		}
+		response.Scores = append(response.Scores, scored)
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fake response: %w", err)
+	}
+	return string(content), nil
+}
+
+// score picks the score and reason for one item
+func (f *FakeClient) score(item Item) (int, string) {
+	f.mu.Lock()
+	value, fixed := f.scores[item.ID]
+	reason, hasReason := f.reasons[item.ID]
+	fn := f.scoreFunc
+	f.mu.Unlock()
+
+	switch {
+	case fixed:
+	case fn != nil:
+		var fnReason string
+		value, fnReason = fn(item)
+		if !hasReason {
+			reason, hasReason = fnReason, true
+		}
+	default:
+		value = DefaultScore
+	}
+
+	if !hasReason {
+		reason = fmt.Sprintf("fake score for item %s", item.ID)
+	}
+	return value, reason
+}
+
+// itemHeader matches the item headers written by the scorer's default prompt format
+var itemHeader = regexp.MustCompile(`(?m)^Item (\d+) \(ID: ([^)\n]*)\):\n`)
+
+// ParseItems extracts the items being scored from a request. It reads the
+// "Item N (ID: x):" blocks of the default prompt format, falling back to the
+// first JSON array of objects with an "id" field for template prompts.
+func ParseItems(req openai.ChatCompletionRequest) []Item {
+	prompt := userPrompt(req)
+
+	if items := parseItemBlocks(prompt); len(items) > 0 {
+		return items
+	}
+	return parseJSONItems(prompt)
+}
+
+// userPrompt returns the content of the first user message, which carries the
+// items; later user messages are follow-ups such as requests for corrected JSON
+func userPrompt(req openai.ChatCompletionRequest) string {
+	for _, msg := range req.Messages {
+		if msg.Role == openai.ChatMessageRoleUser {
+			return msg.Content
+		}
+	}
+	return ""
+}
+
+// parseItemBlocks reads "Item N (ID: x):" blocks
+func parseItemBlocks(prompt string) []Item {
+	matches := itemHeader.FindAllStringSubmatchIndex(prompt, -1)
+	items := make([]Item, 0, len(matches))
+
+	for i, match := range matches {
+		end := len(prompt)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+
+		index, _ := strconv.Atoi(prompt[match[2]:match[3]])
+
+		items = append(items, Item{
+			Index:   index,
+			ID:      prompt[match[4]:match[5]],
+			Content: strings.TrimSpace(prompt[match[1]:end]),
+		})
+	}
+	return items
+}
+
+// parseJSONItems reads the first JSON array of objects carrying an ID
+func parseJSONItems(prompt string) []Item {
+	for start := strings.IndexByte(prompt, '['); start >= 0; {
+		var raw []map[string]interface{}
+		decoder := json.NewDecoder(strings.NewReader(prompt[start:]))
+		if err := decoder.Decode(&raw); err == nil {
+			var items []Item
+			for i, obj := range raw {
+				id := firstString(obj, "id", "ID", "item_id")
+				if id == "" {
+					continue
+				}
+				items = append(items, Item{
+					Index:   i + 1,
+					ID:      id,
+					Content: firstString(obj, "content", "Content", "text"),
+				})
+			}
+			if len(items) > 0 {
+				return items
+			}
+		}
+
+		next := strings.IndexByte(prompt[start+1:], '[')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return nil
+}
+
+// firstString returns the first of keys holding a string or number value
+func firstString(obj map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch v := obj[key].(type) {
+		case string:
+			return v
+		case float64:
+			return fmt.Sprintf("%v", v)
+		}
+	}
+	return ""
+}
+
+// fakeToken splits content into number tokens and runs of other characters
+var fakeToken = regexp.MustCompile(`-?\d+(?:\.\d+)?|[^\d-]+|-`)
+
+// FakeLogprobs tokenizes content with every number as its own token and gives
+// every token a logprob of 0, so each sampled score has probability 1
+func FakeLogprobs(content string) *openai.LogProbs {
+	logprobs := &openai.LogProbs{Content: []openai.LogProb{}}
+	for _, token := range fakeToken.FindAllString(content, -1) {
+		logprobs.Content = append(logprobs.Content, openai.LogProb{
+			Token:       token,
+			LogProb:     0,
+			Bytes:       []byte(token),
+			TopLogProbs: []openai.TopLogProbs{{Token: token, LogProb: 0, Bytes: []byte(token)}},
+		})
+	}
+	return logprobs
+}
+
+// EstimateUsage approximates token usage at four characters per token
+func EstimateUsage(req openai.ChatCompletionRequest, content string) openai.Usage {
+	var promptChars int
+	for _, msg := range req.Messages {
+		promptChars += len(msg.Content)
+	}
+
+	usage := openai.Usage{
+		PromptTokens:     (promptChars + 3) / 4,
+		CompletionTokens: (len(content) + 3) / 4,
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
+}
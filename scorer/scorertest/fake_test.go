@@ -0,0 +1,118 @@
+package scorertest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("FakeClient", func() {
+	var (
+		ctx   context.Context
+		items []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		items = nil
+		for i := 1; i <= 12; i++ {
+			items = append(items, scorer.TextItem{
+				ID:      fmt.Sprintf("item-%d", i),
+				Content: fmt.Sprintf("Post number %d about a gig", i),
+			})
+		}
+	})
+
+	newScorer := func(fake *scorertest.FakeClient) scorer.Scorer {
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+		return s
+	}
+
+	It("should score every item through the real batching code", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"item-3": 95}))
+
+		results, err := newScorer(fake).ScoreTexts(ctx, items, scorer.WithTokenBudget(1000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(12))
+		Expect(results[2].Score).To(Equal(95))
+		Expect(results[0].Score).To(Equal(scorertest.DefaultScore))
+		Expect(fake.Calls()).To(BeNumerically(">", 1))
+	})
+
+	It("should apply rule-based scores", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithScoreFunc(func(item scorertest.Item) (int, string) {
+			return item.Index * 5, "position " + item.ID
+		}))
+
+		results, err := newScorer(fake).ScoreTexts(ctx, items[:3])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[1].Score).To(Equal(10))
+		Expect(results[1].Reason).To(Equal("position item-2"))
+	})
+
+	It("should return scripted API errors in order", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailNext(scorertest.Fault{Status: 503})
+
+		s := newScorer(fake)
+		_, err := s.ScoreTexts(ctx, items[:1])
+		var apiErr *openai.APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.HTTPStatusCode).To(Equal(503))
+
+		_, err = s.ScoreTexts(ctx, items[:1])
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should leave out missing items", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{MissingIDs: []string{"item-2"}})
+
+		results, err := newScorer(fake).ScoreTexts(ctx, items[:3])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Missing).To(BeFalse())
+		Expect(results[1].Missing).To(BeTrue())
+	})
+
+	It("should produce malformed and truncated responses", func() {
+		fake := scorertest.NewFakeClient()
+		fake.FailAlways(scorertest.Fault{MalformedJSON: true})
+		_, err := newScorer(fake).ScoreTexts(ctx, items[:1])
+		Expect(err).To(MatchError(ContainSubstring("failed to parse response JSON")))
+
+		fake.Recover()
+		fake.FailNext(scorertest.Fault{Truncate: true})
+		resp, err := fake.CreateChatCompletion(ctx, fake.Requests()[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Choices[0].FinishReason).To(Equal(openai.FinishReasonLength))
+	})
+
+	It("should honour cancellation while simulating latency", func() {
+		fake := scorertest.NewFakeClient(scorertest.WithLatency(time.Second))
+		short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		_, err := newScorer(fake).ScoreTexts(short, items[:1])
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should parse JSON items from template prompts", func() {
+		req := openai.ChatCompletionRequest{Messages: []openai.ChatCompletionMessage{{
+			Role:    openai.ChatMessageRoleUser,
+			Content: `Score these: [{"id":"a","content":"first"},{"id":"b","content":"second"}]`,
+		}}}
+
+		parsed := scorertest.ParseItems(req)
+		Expect(parsed).To(HaveLen(2))
+		Expect(parsed[1]).To(Equal(scorertest.Item{Index: 2, ID: "b", Content: "second"}))
+	})
+})
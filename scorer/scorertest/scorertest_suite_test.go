@@ -0,0 +1,13 @@
+package scorertest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestScorertest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scorertest Suite")
+}
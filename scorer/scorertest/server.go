@@ -0,0 +1,304 @@
+package scorertest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// Server is an OpenAI-compatible chat completions server backed by a
+// FakeClient. It lets tests drive NewIntegratedScorer end to end over real
+// HTTP, so retry, circuit breaker and metrics behave as they do in production.
+//
+// Scores and faults are scripted on the FakeClient. A Fault with Status is
+// served as that HTTP status with an OpenAI error body, and a Fault with Err
+// drops the connection.
+type Server struct {
+	server *httptest.Server
+	fake   *FakeClient
+	apiKey string
+
+	mu        sync.Mutex
+	rateLimit int
+	served    int
+}
+
+// ServerOption configures a Server
+type ServerOption func(*Server)
+
+// WithAPIKey requires requests to carry this bearer token
+func WithAPIKey(apiKey string) ServerOption {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithRateLimit emits x-ratelimit headers counting down from limit requests
+func WithRateLimit(limit int) ServerOption {
+	return func(s *Server) {
+		s.rateLimit = limit
+	}
+}
+
+// NewServer starts a server answering with fake. The caller must call Close.
+func NewServer(fake *FakeClient, opts ...ServerOption) *Server {
+	if fake == nil {
+		fake = NewFakeClient()
+	}
+
+	s := &Server{fake: fake}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	s.server = httptest.NewServer(mux)
+
+	return s
+}
+
+// BaseURL returns the API base URL to use as Config.BaseURL
+func (s *Server) BaseURL() string {
+	return s.server.URL + "/v1"
+}
+
+// Client returns an HTTP client configured for the server
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Fake returns the FakeClient that produces the server's responses
+func (s *Server) Fake() *FakeClient {
+	return s.fake
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// chatRequest mirrors the parts of a chat completion request the server
+// checks. The schema stays raw because go-openai's request type cannot
+// unmarshal it.
+type chatRequest struct {
+	Model          string                         `json:"model"`
+	Messages       []openai.ChatCompletionMessage `json:"messages"`
+	LogProbs       bool                           `json:"logprobs"`
+	TopLogProbs    int                            `json:"top_logprobs"`
+	ResponseFormat *struct {
+		Type       openai.ChatCompletionResponseFormatType `json:"type"`
+		JSONSchema *struct {
+			Name   string          `json:"name"`
+			Strict bool            `json:"strict"`
+			Schema json.RawMessage `json:"schema"`
+		} `json:"json_schema,omitempty"`
+	} `json:"response_format,omitempty"`
+}
+
+// handleChatCompletions serves POST /v1/chat/completions
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		return
+	}
+
+	if s.apiKey != "" && r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+		writeError(w, http.StatusUnauthorized, "invalid_request_error", "Incorrect API key provided")
+		return
+	}
+
+	var body chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+	if body.Model == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "you must provide a model parameter")
+		return
+	}
+	if len(body.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "messages must not be empty")
+		return
+	}
+
+	if body.TopLogProbs < 0 || body.TopLogProbs > 20 {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "top_logprobs must be between 0 and 20")
+		return
+	}
+	if body.TopLogProbs > 0 && !body.LogProbs {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "logprobs must be true when top_logprobs is set")
+		return
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model:       body.Model,
+		Messages:    body.Messages,
+		LogProbs:    body.LogProbs,
+		TopLogProbs: body.TopLogProbs,
+	}
+
+	var schema *jsonschema.Definition
+	if body.ResponseFormat != nil {
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: body.ResponseFormat.Type}
+
+		if body.ResponseFormat.Type == openai.ChatCompletionResponseFormatTypeJSONSchema {
+			if body.ResponseFormat.JSONSchema == nil || len(body.ResponseFormat.JSONSchema.Schema) == 0 {
+				writeError(w, http.StatusBadRequest, "invalid_request_error", "response_format.json_schema is required")
+				return
+			}
+
+			schema = &jsonschema.Definition{}
+			if err := json.Unmarshal(body.ResponseFormat.JSONSchema.Schema, schema); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid schema: %v", err))
+				return
+			}
+			if body.ResponseFormat.JSONSchema.Strict {
+				if err := checkStrictSchema(*schema, "schema"); err != nil {
+					writeError(w, http.StatusBadRequest, "invalid_request_error",
+						fmt.Sprintf("Invalid schema for response_format '%s': %v", body.ResponseFormat.JSONSchema.Name, err))
+					return
+				}
+			}
+
+			req.ResponseFormat.JSONSchema = &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   body.ResponseFormat.JSONSchema.Name,
+				Strict: body.ResponseFormat.JSONSchema.Strict,
+				Schema: schema,
+			}
+		}
+	}
+
+	s.writeRateLimitHeaders(w)
+
+	resp, err := s.fake.CreateChatCompletion(r.Context(), req)
+	if err != nil {
+		var apiErr *openai.APIError
+		switch {
+		case errors.As(err, &apiErr):
+			if apiErr.HTTPStatusCode == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeError(w, apiErr.HTTPStatusCode, errorType(apiErr.HTTPStatusCode), apiErr.Message)
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			// The client has gone away; nothing to write
+		default:
+			dropConnection(w)
+		}
+		return
+	}
+
+	// Strict schema mode promises conforming output, so catch fakes that break it
+	if schema != nil && len(resp.Choices) > 0 && resp.Choices[0].FinishReason == openai.FinishReasonStop {
+		var data any
+		if json.Unmarshal([]byte(resp.Choices[0].Message.Content), &data) == nil && !jsonschema.Validate(*schema, data) {
+			writeError(w, http.StatusInternalServerError, "server_error", "stub response does not match the requested schema")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// writeRateLimitHeaders emits OpenAI-style request rate-limit headers
+func (s *Server) writeRateLimitHeaders(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rateLimit <= 0 {
+		return
+	}
+
+	s.served++
+	remaining := s.rateLimit - s.served
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	w.Header().Set("x-ratelimit-limit-requests", strconv.Itoa(s.rateLimit))
+	w.Header().Set("x-ratelimit-remaining-requests", strconv.Itoa(remaining))
+	w.Header().Set("x-ratelimit-reset-requests", "1s")
+}
+
+// checkStrictSchema applies the structured outputs rules for strict mode:
+// every object must list all of its properties as required and disallow
+// additional properties
+func checkStrictSchema(schema jsonschema.Definition, path string) error {
+	switch schema.Type {
+	case jsonschema.Object:
+		if schema.AdditionalProperties != false {
+			return fmt.Errorf("%s: 'additionalProperties' is required to be supplied and to be false", path)
+		}
+		required := make(map[string]bool, len(schema.Required))
+		for _, name := range schema.Required {
+			required[name] = true
+		}
+		for name, property := range schema.Properties {
+			if !required[name] {
+				return fmt.Errorf("%s: 'required' is required to include every property, missing '%s'", path, name)
+			}
+			if err := checkStrictSchema(property, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case jsonschema.Array:
+		if schema.Items == nil {
+			return fmt.Errorf("%s: array schema must define 'items'", path)
+		}
+		return checkStrictSchema(*schema.Items, path+"[]")
+	case "":
+		return fmt.Errorf("%s: schema must have a 'type' key", path)
+	}
+	return nil
+}
+
+// errorType maps an HTTP status to the OpenAI error type string
+func errorType(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_exceeded"
+	case status == http.StatusUnauthorized:
+		return "invalid_request_error"
+	case status >= 500:
+		return "server_error"
+	default:
+		return "invalid_request_error"
+	}
+}
+
+// writeError writes an OpenAI-style error body
+func writeError(w http.ResponseWriter, status int, errType, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"message": message,
+			"type":    errType,
+			"code":    strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"),
+		},
+	})
+}
+
+// dropConnection closes the connection without a response to simulate a network failure
+func dropConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeError(w, http.StatusBadGateway, "server_error", "connection dropped")
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	_ = conn.Close()
+}
@@ -0,0 +1,158 @@
+package scorertest_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		ctx    context.Context
+		fake   *scorertest.FakeClient
+		server *scorertest.Server
+		items  []scorer.TextItem
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = scorertest.NewFakeClient(scorertest.WithScores(map[string]int{"1": 82}))
+		server = scorertest.NewServer(fake, scorertest.WithAPIKey("test-key"), scorertest.WithRateLimit(100))
+		items = []scorer.TextItem{{ID: "1", Content: "Farmers market every Saturday"}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	integratedConfig := func() scorer.Config {
+		return scorer.NewDefaultConfig("test-key").
+			WithBaseURL(server.BaseURL()).
+			WithRetryConfig(&scorer.RetryConfig{
+				MaxAttempts:  3,
+				Strategy:     scorer.RetryStrategyConstant,
+				InitialDelay: 10 * time.Millisecond,
+				MaxDelay:     50 * time.Millisecond,
+			}).
+			WithCircuitBreaker()
+	}
+
+	It("should score end to end through the integrated scorer", func() {
+		s, err := scorer.NewIntegratedScorer(integratedConfig())
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(82))
+		Expect(fake.Requests()[0].ResponseFormat.JSONSchema.Strict).To(BeTrue())
+	})
+
+	It("should retry server errors over real HTTP", func() {
+		fake.FailNext(scorertest.Fault{Status: 503}, scorertest.Fault{Status: 429})
+
+		s, err := scorer.NewIntegratedScorer(integratedConfig())
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results[0].Score).To(Equal(82))
+		Expect(fake.Calls()).To(Equal(3))
+	})
+
+	It("should open the circuit breaker after repeated failures", func() {
+		fake.FailAlways(scorertest.Fault{Status: 500})
+
+		cfg := integratedConfig()
+		cfg.EnableRetry = false
+		s, err := scorer.NewIntegratedScorer(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := 0; i < 5; i++ {
+			_, err = s.ScoreTexts(ctx, items)
+			var apiErr *openai.APIError
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.HTTPStatusCode).To(Equal(500))
+		}
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).To(MatchError(ContainSubstring("circuit breaker is open")))
+		Expect(fake.Calls()).To(Equal(5))
+	})
+
+	It("should reject requests with the wrong API key", func() {
+		s, err := scorer.NewScorer(scorer.NewDefaultConfig("wrong-key").WithBaseURL(server.BaseURL()))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		var apiErr *openai.APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.HTTPStatusCode).To(Equal(401))
+		Expect(fake.Calls()).To(BeZero())
+	})
+
+	It("should surface dropped connections as transport errors", func() {
+		fake.FailNext(scorertest.Fault{Err: errors.New("boom")})
+
+		s, err := scorer.NewScorer(scorer.NewDefaultConfig("test-key").WithBaseURL(server.BaseURL()))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, items)
+		Expect(err).To(HaveOccurred())
+		var apiErr *openai.APIError
+		Expect(errors.As(err, &apiErr)).To(BeFalse())
+	})
+
+	It("should return usage and rate-limit headers", func() {
+		client := openai.NewClientWithConfig(func() openai.ClientConfig {
+			cfg := openai.DefaultConfig("test-key")
+			cfg.BaseURL = server.BaseURL()
+			return cfg
+		}())
+
+		resp, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+			Model:    openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Item 1 (ID: 1):\nhello"}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Usage.TotalTokens).To(BeNumerically(">", 0))
+		Expect(resp.Header().Get("x-ratelimit-limit-requests")).To(Equal("100"))
+		Expect(resp.Header().Get("x-ratelimit-remaining-requests")).To(Equal("99"))
+	})
+
+	It("should reject schemas that strict mode would refuse", func() {
+		client := openai.NewClientWithConfig(func() openai.ClientConfig {
+			cfg := openai.DefaultConfig("test-key")
+			cfg.BaseURL = server.BaseURL()
+			return cfg
+		}())
+
+		_, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+			Model:    openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+			ResponseFormat: &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+				JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+					Name:   "loose",
+					Strict: true,
+					Schema: &jsonschema.Definition{
+						Type:       jsonschema.Object,
+						Properties: map[string]jsonschema.Definition{"score": {Type: jsonschema.Integer}},
+						Required:   []string{"score"},
+					},
+				},
+			},
+		})
+		var apiErr *openai.APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.HTTPStatusCode).To(Equal(400))
+		Expect(apiErr.Message).To(ContainSubstring("additionalProperties"))
+	})
+})
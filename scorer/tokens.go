@@ -0,0 +1,218 @@
+package scorer
+
+import (
+	"log/slog"
+	"math"
+	"unicode"
+)
+
+const (
+	// DefaultBatchTokenBudget is the estimated tokens per request, covering
+	// the prompt, the items and the expected output, when neither
+	// Config.BatchTokenBudget nor WithTokenBudget sets one
+	DefaultBatchTokenBudget = 8_000
+
+	// DefaultCharsPerToken is the characters-per-token ratio assumed for
+	// models without one in their capabilities. It is on the low side so
+	// unknown tokenizers produce smaller batches rather than overflows.
+	DefaultCharsPerToken = 3.5
+)
+
+// Expected output tokens per item, used to reserve room for the response
+const (
+	scoreOutputTokens     = 80  // item_id, score and a one or two sentence reason
+	dimensionOutputTokens = 50  // One rubric dimension's score and reason
+	entitiesOutputTokens  = 150 // Venues, locations, dates and prices
+	evidenceQuoteTokens   = 40  // One evidence quote
+	classifyOutputTokens  = 60  // Labels, confidence and reason
+	extractionOutputRatio = 2   // Extracted data relative to the size of its schema
+)
+
+// WithTokenBudget sets the estimated tokens per request for this call,
+// overriding Config.BatchTokenBudget
+func WithTokenBudget(tokens int) ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.tokenBudget = tokens
+	}
+}
+
+// estimateTokens approximates how many tokens a model's tokenizer splits text
+// into. Runs of letters are split into chunks of charsPerToken, digits into
+// groups of three, and punctuation, symbols and characters from scripts
+// written without spaces count as a token each. Whitespace is absorbed into
+// the following word, as byte-pair encoders do.
+func estimateTokens(text string, charsPerToken float64) int {
+	if charsPerToken <= 0 {
+		charsPerToken = DefaultCharsPerToken
+	}
+
+	tokens := 0
+	letters, digits := 0, 0
+	flush := func() {
+		tokens += int(math.Ceil(float64(letters) / charsPerToken))
+		tokens += (digits + 2) / 3
+		letters, digits = 0, 0
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.IsDigit(r):
+			if letters > 0 {
+				flush()
+			}
+			digits++
+		case unicode.IsLetter(r) && !isUnspacedScript(r):
+			if digits > 0 {
+				flush()
+			}
+			letters++
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// isUnspacedScript reports whether r belongs to a script written without
+// spaces between words, which tokenizers split roughly per character
+func isUnspacedScript(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai)
+}
+
+// charsPerToken returns the model's characters-per-token ratio, or the
+// default for models without one
+func charsPerToken(model string) float64 {
+	if caps, ok := LookupModel(model); ok && caps.CharsPerToken > 0 {
+		return caps.CharsPerToken
+	}
+	return DefaultCharsPerToken
+}
+
+// batchBudget packs items into batches whose estimated prompt and output fit
+// a token budget
+type batchBudget struct {
+	limit         int     // Estimated tokens per request: prompt, items and output
+	overhead      int     // Prompt text and instructions sent with every batch
+	outputPerItem int     // Expected output tokens per item
+	maxOutput     int     // Output token limit per request (0 = none)
+	charsPerToken float64 // Tokenizer ratio for the model
+}
+
+// newBatchBudget builds the budget for a request from the caller's override,
+// the config and the model's limits. Prompts are the texts sent with every
+// batch, and outputPerItem is the expected response size for one item.
+func (c Config) newBatchBudget(options *scoringOptions, prompts []string, outputPerItem int) batchBudget {
+	model := c.resolveModel(options)
+	budget := batchBudget{
+		limit:         DefaultBatchTokenBudget,
+		outputPerItem: outputPerItem,
+		maxOutput:     c.MaxOutputTokens,
+		charsPerToken: charsPerToken(model),
+	}
+
+	if c.BatchTokenBudget > 0 {
+		budget.limit = c.BatchTokenBudget
+	}
+	if options != nil && options.tokenBudget > 0 {
+		budget.limit = options.tokenBudget
+	}
+	if options != nil && options.maxOutputTokens > 0 {
+		budget.maxOutput = options.maxOutputTokens
+	}
+
+	if caps, ok := LookupModel(model); ok {
+		if caps.ContextWindow > 0 && budget.limit > caps.ContextWindow {
+			budget.limit = caps.ContextWindow
+		}
+		if budget.maxOutput == 0 {
+			budget.maxOutput = caps.MaxOutputTokens
+		}
+	}
+
+	for _, prompt := range prompts {
+		budget.overhead += estimateTokens(prompt, budget.charsPerToken)
+	}
+
+	return budget
+}
+
+// scoringBudget returns the batch budget for a scoring request, reserving
+// room for the prompt, the instructions of any add-ons and their output
+func (c Config) scoringBudget(options *scoringOptions) batchBudget {
+	prompt := batchScorePrompt
+	if c.PromptText != "" {
+		prompt = c.PromptText
+	}
+	system := systemPrompt
+	if options != nil {
+		if options.promptText != "" {
+			prompt = options.promptText
+		}
+		if options.systemPrompt != "" {
+			system = options.systemPrompt
+		}
+	}
+
+	prompts := []string{system, prompt}
+	output := scoreOutputTokens
+
+	if rubric := c.resolveRubric(options); rubric != nil {
+		prompts = append(prompts, rubric.instructions())
+		output += dimensionOutputTokens * len(rubric.Dimensions)
+	}
+	if entities := c.resolveEntities(options); entities != nil {
+		prompts = append(prompts, entities.instructions())
+		output += entitiesOutputTokens
+	}
+	if evidence := c.resolveEvidence(options); evidence != nil {
+		prompts = append(prompts, evidence.instructions())
+		output += evidenceQuoteTokens * evidence.maxQuotes()
+	}
+
+	return c.newBatchBudget(options, prompts, output)
+}
+
+// split packs items into batches in input order. A batch closes when the next
+// item would take its estimate over the budget or its expected output over the
+// output limit. An item too large for any batch is sent on its own.
+func (b batchBudget) split(items []TextItem) [][]TextItem {
+	available := b.limit - b.overhead
+
+	var batches [][]TextItem
+	start, used := 0, 0
+	for i, item := range items {
+		cost := estimateTokens(formatItemsAsText([]TextItem{item}), b.charsPerToken) + b.outputPerItem
+		count := i - start
+
+		overBudget := used+cost > available
+		overOutput := b.maxOutput > 0 && (count+1)*b.outputPerItem > b.maxOutput
+		if count > 0 && (overBudget || overOutput) {
+			batches = append(batches, items[start:i])
+			start, used = i, 0
+		}
+
+		if cost > available {
+			slog.Warn("Item exceeds the batch token budget, sending it alone",
+				"item_id", item.ID,
+				"estimated_tokens", cost+b.overhead,
+				"budget", b.limit)
+		}
+		used += cost
+	}
+	if start < len(items) {
+		batches = append(batches, items[start:])
+	}
+
+	slog.Debug("Split items by token budget",
+		"items", len(items),
+		"batches", len(batches),
+		"budget", b.limit,
+		"overhead", b.overhead)
+
+	return batches
+}
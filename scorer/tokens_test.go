@@ -0,0 +1,158 @@
+// Package scorer_test covers token-budget batching: packing short items into
+// few requests, splitting long ones, and the budget overrides and model limits
+// that bound each batch.
+package scorer_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/JohnPlummer/llm-client/scorer"
+	"github.com/JohnPlummer/llm-client/scorer/scorertest"
+)
+
+var _ = Describe("Token budget batching", func() {
+	var ctx context.Context
+
+	// makeItems builds n items whose content repeats a short sentence
+	makeItems := func(n, repeat int) []scorer.TextItem {
+		items := make([]scorer.TextItem, n)
+		for i := range items {
+			items[i] = scorer.TextItem{
+				ID:      fmt.Sprintf("item-%d", i),
+				Content: strings.Repeat("Live jazz at the Blue Note tonight. ", repeat),
+			}
+		}
+		return items
+	}
+
+	// itemsPerRequest counts the items in each request's prompt
+	itemsPerRequest := func(requests []openai.ChatCompletionRequest) []int {
+		counts := make([]int, len(requests))
+		for i, req := range requests {
+			counts[i] = strings.Count(req.Messages[len(req.Messages)-1].Content, "(ID: ")
+		}
+		return counts
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should pack short items into a single request", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := s.ScoreTexts(ctx, makeItems(30, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(30))
+		Expect(fake.Calls()).To(Equal(1))
+	})
+
+	It("should split long items across requests in input order", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		items := makeItems(10, 250) // About 9,000 characters each
+		results, err := s.ScoreTexts(ctx, items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(BeNumerically(">=", 3))
+		for i, result := range results {
+			Expect(result.Item.ID).To(Equal(items[i].ID))
+		}
+		for _, count := range itemsPerRequest(fake.Requests()) {
+			Expect(count).To(BeNumerically("<=", 4))
+		}
+	})
+
+	It("should use the budget from the config", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithBatchTokenBudget(1_500))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, makeItems(30, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(BeNumerically(">", 1))
+	})
+
+	It("should let a per-request budget override the config", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake}.WithBatchTokenBudget(1_500))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, makeItems(30, 1), scorer.WithTokenBudget(100_000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(Equal(1))
+	})
+
+	It("should send an item larger than the budget on its own", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake})
+		Expect(err).ToNot(HaveOccurred())
+
+		items := makeItems(3, 1)
+		items[1].Content = strings.Repeat("word ", 1_900)
+		_, err = s.ScoreTexts(ctx, items, scorer.WithTokenBudget(1_000))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(itemsPerRequest(fake.Requests())).To(Equal([]int{1, 1, 1}))
+	})
+
+	It("should keep the expected output within the output token limit", func() {
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake, MaxOutputTokens: 200})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, makeItems(6, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(itemsPerRequest(fake.Requests())).To(Equal([]int{2, 2, 2}))
+	})
+
+	It("should cap the budget at the model's context window", func() {
+		Expect(scorer.RegisterModel(scorer.ModelCapabilities{
+			Name:              "tiny-context-model",
+			StructuredOutputs: true,
+			ContextWindow:     1_500,
+		})).To(Succeed())
+
+		fake := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: fake, Model: "tiny-context-model"})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = s.ScoreTexts(ctx, makeItems(30, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.Calls()).To(BeNumerically(">", 1))
+	})
+
+	It("should reserve room for rubric output", func() {
+		plain := scorertest.NewFakeClient()
+		s, err := scorer.NewScorer(scorer.Config{Client: plain}.WithBatchTokenBudget(3_000))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = s.ScoreTexts(ctx, makeItems(30, 1))
+		Expect(err).ToNot(HaveOccurred())
+
+		rubric := scorertest.NewFakeClient()
+		s, err = scorer.NewScorer(scorer.Config{Client: rubric}.WithBatchTokenBudget(3_000).WithRubric(scorer.Rubric{
+			Dimensions: []scorer.Dimension{{Name: "relevance"}, {Name: "specificity"}, {Name: "timeliness"}},
+		}))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = s.ScoreTexts(ctx, makeItems(30, 1))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(rubric.Calls()).To(BeNumerically(">", plain.Calls()))
+	})
+
+	It("should reject a negative budget", func() {
+		err := scorer.Config{APIKey: "key", BatchTokenBudget: -1}.Validate()
+		Expect(err).To(MatchError("BatchTokenBudget must be non-negative"))
+
+		Expect(scorer.RegisterModel(scorer.ModelCapabilities{Name: "bad-ratio", CharsPerToken: -1})).
+			To(MatchError("CharsPerToken must be non-negative"))
+	})
+})
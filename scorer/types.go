@@ -3,6 +3,8 @@ package scorer
 import (
 	"context"
 	"errors"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
@@ -18,9 +20,35 @@ type TextItem struct {
 
 // ScoredItem represents a text item with its AI-generated score
 type ScoredItem struct {
-	Item   TextItem // Original text item
-	Score  int      // Score between 0-100
-	Reason string   // AI explanation for the score
+	Item    TextItem // Original text item
+	Score   int      // Score on the configured scale (0-100 by default), rounded on decimal scales
+	Value   float64  // Exact score on the configured scale
+	Reason  string   // AI explanation for the score
+	Backend string   // Backend that produced the score, as "provider/model"
+	Missing bool     // The item was not scored, even when re-asked; Score and Value are unset
+	Err     error    // Why a Missing item was not scored: ErrItemMissing, a *RefusalError or a *BatchError
+
+	// Rubric results, set only when scoring with a Rubric
+	Dimensions map[string]DimensionScore // Per-dimension scores keyed by dimension name
+	Aggregate  float64                   // Weighted aggregate on a 0-100 scale; Score and Value map it onto the scale
+
+	// Logprob results, set only when scoring with logprobs and the backend returns them
+	ExpectedScore float64 // Probability-weighted score on the configured scale
+	Confidence    float64 // Probability of the sampled score among candidate scores, 0-1 (0 = unavailable)
+
+	// Ensemble results, set only when scoring with an ensemble
+	Samples     []float64 // Each run's score on the configured scale, in run order
+	Variance    float64   // Population variance of Samples
+	Spread      float64   // Highest minus lowest sample
+	NeedsReview bool      // Spread reached the ensemble's ReviewSpread
+
+	// Entities mentioned in the item, set only when scoring with an EntityConfig
+	Entities *Entities
+
+	// Quotes supporting the score, set only when scoring with an EvidenceConfig
+	Evidence []Evidence
+
+	batch int // Index of the batch the item was sent in, for partial results
 }
 
 // Scorer provides methods to score generic text items
@@ -28,7 +56,10 @@ type Scorer interface {
 	// ScoreTexts scores a slice of text items
 	ScoreTexts(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ScoredItem, error)
 
-	// ScoreTextsWithOptions scores text items with runtime options
+	// ScoreTextsWithOptions scores text items with runtime options. When some
+	// batches fail, the results of every item are still returned in input
+	// order alongside the error, with the failed batches' items Missing and
+	// their Err set to a *BatchError.
 	ScoreTextsWithOptions(ctx context.Context, items []TextItem, opts ...ScoringOption) ([]ScoredItem, error)
 
 	// GetHealth returns the current health status of the scorer
@@ -44,16 +75,47 @@ type HealthStatus struct {
 
 // Config holds the configuration for the scorer
 type Config struct {
-	APIKey               string                // OpenAI API key (required)
-	Model                string                // OpenAI model to use
+	Provider             Provider              // LLM provider backend (default: ProviderOpenAI)
+	APIKey               string                // Provider API key (required)
+	Model                string                // Model to use
 	PromptText           string                // Custom prompt template
 	MaxConcurrent        int                   // Maximum concurrent API calls
 	MaxContentLength     int                   // Maximum content length per text item (0 = use default)
+	BatchTokenBudget     int                   // Estimated tokens per request: prompt, items and expected output (0 = DefaultBatchTokenBudget)
+	MaxReasks            int                   // Follow-up requests for items missing from a response (0 = DefaultMaxReasks, negative = never re-ask)
+	MaxJSONCorrections   int                   // Follow-up requests asking the model to fix invalid JSON (0 = DefaultMaxJSONCorrections, negative = never ask)
 	EnableCircuitBreaker bool                  // Enable circuit breaker pattern
 	EnableRetry          bool                  // Enable retry with backoff
 	Timeout              time.Duration         // Request timeout
 	CircuitBreakerConfig *CircuitBreakerConfig // Circuit breaker configuration
 	RetryConfig          *RetryConfig          // Retry configuration
+
+	// Client connection settings
+	Client     OpenAIClient // Pre-built client to use instead of constructing one (APIKey optional when set)
+	BaseURL    string       // API base URL override for gateways and OpenAI-compatible servers
+	OrgID      string       // OpenAI organization ID sent as the OpenAI-Organization header
+	ProjectID  string       // OpenAI project ID sent as the OpenAI-Project header
+	HTTPClient *http.Client // Custom HTTP client for proxies, TLS settings or transport tuning
+	OutputMode OutputMode   // Structured output mode (default: auto-detect per provider)
+
+	AzureConfig *AzureConfig // Azure OpenAI resource settings (required when Provider is ProviderAzure)
+
+	// Generation parameters, translated per model family (nil/0/empty = provider default)
+	Temperature     *float32        // Sampling temperature; nil = model default, 0 = greedy (see WithTemperature); dropped for models that reject it
+	MaxOutputTokens int             // Output token limit; sent as max_completion_tokens to reasoning models
+	ReasoningEffort ReasoningEffort // Reasoning effort for reasoning models; ignored by others
+
+	Rubric *Rubric     // Default rubric for multi-criteria scoring (nil = single score)
+	Scale  *ScoreScale // Score scale for results, schema and metrics (nil = DefaultScale, 0-100)
+
+	Logprobs bool // Request token logprobs to compute ExpectedScore and Confidence
+
+	Entities *EntityConfig   // Extract venues, locations, dates and prices with each score (nil = off)
+	Evidence *EvidenceConfig // Ask for quotes supporting each score, verified against the content (nil = off)
+
+	// Fallbacks are complete backend configs tried in order when this backend is
+	// unavailable. Each gets its own retry and circuit breaker.
+	Fallbacks []Config
 }
 
 // CircuitBreakerConfig holds circuit breaker settings
@@ -73,6 +135,36 @@ type RetryConfig struct {
 	MaxDelay     time.Duration // Maximum delay between retries
 }
 
+// Provider identifies the LLM API backend a scorer talks to
+type Provider string
+
+const (
+	ProviderOpenAI    Provider = "openai"    // OpenAI Chat Completions API
+	ProviderAnthropic Provider = "anthropic" // Anthropic Messages API
+	ProviderLocal     Provider = "local"     // Self-hosted OpenAI-compatible server (Ollama, llama.cpp)
+	ProviderAzure     Provider = "azure"     // Azure OpenAI resource with per-model deployments
+)
+
+// OutputMode selects how structured JSON output is requested from the model
+type OutputMode string
+
+const (
+	OutputModeAuto       OutputMode = ""            // Pick per provider, falling back when the server rejects a mode
+	OutputModeJSONSchema OutputMode = "json_schema" // Strict JSON schema response format
+	OutputModeJSONObject OutputMode = "json_object" // JSON mode with the schema described in the prompt
+	OutputModeGrammar    OutputMode = "grammar"     // Grammar-constrained output via the server's json_schema extension
+	OutputModeText       OutputMode = "text"        // No response format; the schema is described in the prompt
+)
+
+// ReasoningEffort controls how much reasoning a reasoning model does before answering
+type ReasoningEffort string
+
+const (
+	ReasoningEffortLow    ReasoningEffort = "low"
+	ReasoningEffortMedium ReasoningEffort = "medium"
+	ReasoningEffortHigh   ReasoningEffort = "high"
+)
+
 // RetryStrategy defines the backoff strategy for retries
 type RetryStrategy string
 
@@ -93,9 +185,19 @@ type OpenAIClient interface {
 
 // Internal scorer implementation
 type scorer struct {
-	client OpenAIClient
-	config Config
-	prompt string
+	client  OpenAIClient
+	config  Config
+	prompt  string
+	system  string // System prompt with the scale filled in
+	metrics *MetricsRecorder
+
+	// fallbacks are the per-call fallback backends tried in order when this
+	// backend is unavailable; only set by newPerCallScorer
+	fallbacks []*scorer
+
+	// detectedModes caches the output mode negotiated per model when OutputMode
+	// is auto, so later batches skip modes the server already rejected
+	detectedModes sync.Map
 }
 
 // Error definitions
@@ -125,9 +227,18 @@ type ScoringOption func(*scoringOptions)
 
 // scoringOptions holds the options for a scoring request (internal)
 type scoringOptions struct {
-	model        string                 // Model to use for this request
-	promptText   string                 // Custom prompt for this request
-	extraContext map[string]interface{} // Additional context data
+	model           string                 // Model to use for this request
+	promptText      string                 // Custom prompt for this request
+	extraContext    map[string]interface{} // Additional context data
+	temperature     *float32               // Sampling temperature override; nil keeps the config's
+	maxOutputTokens int                    // Output token limit override
+	reasoningEffort ReasoningEffort        // Reasoning effort override
+	rubric          *Rubric                // Rubric override for multi-criteria scoring
+	systemPrompt    string                 // System prompt override (internal modes such as ranking)
+	logprobs        bool                   // Request token logprobs for expected scores
+	entities        *EntityConfig          // Entity extraction override
+	evidence        *EvidenceConfig        // Evidence quote override
+	tokenBudget     int                    // Batch token budget override
 }
 
 // ScoringOptions is the exported version for testing (uppercase)
@@ -157,3 +268,27 @@ func WithExtraContext(context map[string]interface{}) ScoringOption {
 		opts.extraContext = context
 	}
 }
+
+// WithTemperature sets the sampling temperature for this scoring request,
+// overriding Config.Temperature. Zero requests greedy decoding. Models that
+// only support their default temperature ignore it.
+func WithTemperature(temperature float32) ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.temperature = &temperature
+	}
+}
+
+// WithMaxOutputTokens sets the output token limit for this scoring request
+func WithMaxOutputTokens(tokens int) ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.maxOutputTokens = tokens
+	}
+}
+
+// WithReasoningEffort sets the reasoning effort for this scoring request.
+// Only reasoning models use it.
+func WithReasoningEffort(effort ReasoningEffort) ScoringOption {
+	return func(opts *scoringOptions) {
+		opts.reasoningEffort = effort
+	}
+}